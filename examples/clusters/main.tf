@@ -33,8 +33,36 @@ resource "pulsar_cluster" "my_cluster" {
     web_service_url    = "http://localhost:8080"
     broker_service_url = "http://localhost:6050"
     peer_clusters = [
-      "skrulls",
-      "krees"
+      "standalone"
     ]
   }
 }
+
+// the peers of clusters replicating to each other are managed on their own
+resource "pulsar_cluster" "skrulls" {
+  cluster = "skrulls"
+
+  cluster_data {
+    web_service_url    = "http://skrulls:8080"
+    broker_service_url = "http://skrulls:6050"
+  }
+}
+
+resource "pulsar_cluster" "krees" {
+  cluster = "krees"
+
+  cluster_data {
+    web_service_url    = "http://krees:8080"
+    broker_service_url = "http://krees:6050"
+  }
+}
+
+resource "pulsar_cluster_peers" "skrulls" {
+  cluster       = pulsar_cluster.skrulls.id
+  peer_clusters = [pulsar_cluster.krees.id]
+}
+
+resource "pulsar_cluster_peers" "krees" {
+  cluster       = pulsar_cluster.krees.id
+  peer_clusters = [pulsar_cluster.skrulls.id]
+}
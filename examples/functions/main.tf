@@ -58,13 +58,12 @@ resource "pulsar_function" "function-1" {
   log_topic = "public/default/lt"
   timeout_ms = 6666
 
-  secrets = jsonencode(
-  {
-    "SECRET1": {
-       "path": "sectest"
-       "key": "hello"
-    }
-  })
+  secret {
+    name = "SECRET1"
+    path = "sectest"
+    key  = "hello"
+  }
+
   custom_runtime_options = jsonencode(
   {
       "env": {
@@ -40,7 +40,7 @@ resource "pulsar_cluster" "test_cluster" {
 resource "pulsar_tenant" "test_tenant" {
   tenant = "thanos"
   allowed_clusters = [
-    pulsar_cluster.test_cluster.cluster,
+    pulsar_cluster.test_cluster.id,
   "standalone"]
 }
 
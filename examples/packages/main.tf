@@ -0,0 +1,56 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+terraform {
+  required_providers {
+    pulsar = {
+      version = "0.1.3"
+      source = "registry.terraform.io/streamnative/pulsar"
+    }
+  }
+}
+
+
+provider "pulsar" {
+  web_service_url = "http://localhost:8080"
+}
+
+resource "pulsar_package" "api-examples" {
+  type        = "function"
+  tenant      = "public"
+  namespace   = "default"
+  name        = "api-examples"
+  version     = "v2"
+  path        = "api-examples.jar"
+  description = "api-examples"
+}
+
+resource "pulsar_function" "exclamation" {
+  tenant    = "public"
+  namespace = "default"
+  name      = "exclamation"
+  jar       = pulsar_package.api-examples.package_url
+  classname = "org.apache.pulsar.functions.api.examples.ExclamationFunction"
+  inputs    = ["persistent://public/default/exclamation-input"]
+  output    = "persistent://public/default/exclamation-output"
+
+  parallelism           = 1
+  processing_guarantees = "ATLEAST_ONCE"
+  subscription_position = "Latest"
+  auto_ack              = true
+  cleanup_subscription  = true
+}
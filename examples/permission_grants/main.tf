@@ -0,0 +1,42 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+terraform {
+  required_providers {
+    pulsar = {
+      version = "0.1.3"
+      source = "registry.terraform.io/streamnative/pulsar"
+    }
+  }
+}
+
+
+provider "pulsar" {
+  web_service_url = "http://localhost:8080"
+}
+
+resource "pulsar_permission_grant" "namespace-consumer" {
+  namespace = "public/default"
+  role      = "some-consumer-role"
+  actions   = ["consume"]
+}
+
+resource "pulsar_permission_grant" "topic-producer" {
+  topic   = "persistent://public/default/partition-topic"
+  role    = "some-producer-role"
+  actions = ["produce"]
+}
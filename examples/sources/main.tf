@@ -37,7 +37,7 @@ resource "pulsar_source" "source-1" {
 
   archive = "https://www.apache.org/dyn/mirrors/mirrors.cgi?action=download&filename=pulsar/pulsar-2.10.4/connectors/pulsar-io-file-2.10.4.nar"
 
-  destination_topic_name = "source-1-topic"
+  destination_topic_name = "persistent://public/default/source-1-topic"
 
   processing_guarantees = "EFFECTIVELY_ONCE"
 
@@ -0,0 +1,95 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package exporter
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	"github.com/streamnative/terraform-provider-pulsar/pulsar"
+)
+
+// CommandName is the argument of the provider binary running the exporter instead of the plugin
+const CommandName = "export"
+
+// Run implements the export command, e.g.
+//
+//	terraform-provider-pulsar export -web-service-url http://localhost:8080 -tenant public -output pulsar.tf
+//
+// The authentication is configured with the same environment variables as the provider, e.g.
+// PULSAR_TOKEN. The exit code is returned.
+func Run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet(CommandName, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	webServiceURL := flags.String("web-service-url", "",
+		"the web service url of the cluster, defaults to the environment of the provider")
+	tenants := flags.String("tenant", "", "the comma separated tenants to export, all of them by default")
+	output := flags.String("output", "-", "the file to write the configuration to, - for the standard output")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	provider := pulsar.Provider()
+	config := make(map[string]interface{})
+	if *webServiceURL != "" {
+		config["web_service_url"] = *webServiceURL
+	}
+	if diags := provider.Configure(ctx, terraform.NewResourceConfigRaw(config)); diags.HasError() {
+		for _, d := range diags {
+			fmt.Fprintf(stderr, "%s: %s\n", d.Summary, d.Detail)
+		}
+		return 1
+	}
+
+	var tenantList []string
+	for _, tenant := range strings.Split(*tenants, ",") {
+		if tenant = strings.TrimSpace(tenant); tenant != "" {
+			tenantList = append(tenantList, tenant)
+		}
+	}
+
+	e, err := New(provider, tenantList)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	w := stdout
+	if *output != "-" {
+		f, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintf(stderr, "ERROR_CREATE_OUTPUT: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if err := e.Export(ctx, w); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,224 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package exporter generates the Terraform configuration of the objects of an existing Pulsar
+// cluster, along with the import blocks bringing them under management.
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/utils"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/streamnative/terraform-provider-pulsar/pulsar"
+)
+
+// systemNamespaces are managed by the brokers and the function workers themselves
+var systemNamespaces = map[string]bool{
+	"pulsar/system":    true,
+	"public/functions": true,
+}
+
+// Exporter walks the tenants, namespaces, topics, functions, sinks and sources of a cluster. Each
+// object is read through the importer of its resource, so the generated configuration matches
+// what an import followed by a plan reads.
+type Exporter struct {
+	provider *schema.Provider
+	clients  pulsar.PulsarClientBundle
+	tenants  []string
+
+	labels map[string]bool
+	errs   *multierror.Error
+}
+
+// New returns an exporter for a configured provider, every tenant is exported when none is given
+func New(provider *schema.Provider, tenants []string) (*Exporter, error) {
+	clients, ok := provider.Meta().(pulsar.PulsarClientBundle)
+	if !ok {
+		return nil, fmt.Errorf("ERROR_EXPORT: the provider is not configured")
+	}
+
+	return &Exporter{
+		provider: provider,
+		clients:  clients,
+		tenants:  tenants,
+		labels:   make(map[string]bool),
+	}, nil
+}
+
+// Export writes the configuration of the cluster. The objects which cannot be read are reported
+// in the returned error, the others are written anyway.
+func (e *Exporter) Export(ctx context.Context, w io.Writer) error {
+	tenants := e.tenants
+	if len(tenants) == 0 {
+		var err error
+		tenants, err = e.clients.Client.Tenants().List()
+		if err != nil {
+			return fmt.Errorf("ERROR_LIST_TENANTS: %w", err)
+		}
+	}
+
+	file := hclwrite.NewEmptyFile()
+	for _, tenant := range tenants {
+		e.exportTenant(ctx, file.Body(), tenant)
+	}
+
+	if _, err := w.Write(file.Bytes()); err != nil {
+		return fmt.Errorf("ERROR_WRITE_CONFIGURATION: %w", err)
+	}
+
+	return e.errs.ErrorOrNil()
+}
+
+func (e *Exporter) exportTenant(ctx context.Context, body *hclwrite.Body, tenant string) {
+	namespaces, err := e.clients.Client.Namespaces().GetNamespaces(tenant)
+	if err != nil {
+		e.fail(fmt.Errorf("ERROR_LIST_NAMESPACES %s: %w", tenant, err))
+		return
+	}
+
+	if tenant != "pulsar" {
+		e.exportResource(ctx, body, "pulsar_tenant", tenant)
+	}
+
+	for _, namespace := range namespaces {
+		if systemNamespaces[namespace] {
+			continue
+		}
+		e.exportResource(ctx, body, "pulsar_namespace", namespace)
+		e.exportTopics(ctx, body, namespace)
+		e.exportInstances(ctx, body, namespace)
+	}
+}
+
+func (e *Exporter) exportTopics(ctx context.Context, body *hclwrite.Body, namespace string) {
+	ns, err := utils.GetNamespaceName(namespace)
+	if err != nil {
+		e.fail(fmt.Errorf("ERROR_PARSE_NAMESPACE_NAME %s: %w", namespace, err))
+		return
+	}
+
+	partitioned, nonPartitioned, err := e.clients.Client.Topics().List(*ns)
+	if err != nil {
+		e.fail(fmt.Errorf("ERROR_LIST_TOPICS %s: %w", namespace, err))
+		return
+	}
+
+	isPartitioned := make(map[string]bool, len(partitioned))
+	for _, topic := range partitioned {
+		isPartitioned[topic] = true
+	}
+
+	for _, topic := range append(partitioned, nonPartitioned...) {
+		topicName, err := utils.GetTopicName(topic)
+		if err != nil {
+			e.fail(fmt.Errorf("ERROR_PARSE_TOPIC_NAME %s: %w", topic, err))
+			continue
+		}
+		// the system topics, e.g. __change_events, are created by the brokers
+		if strings.HasPrefix(topicName.GetLocalName(), "__") {
+			continue
+		}
+		// the partitions are listed along with the non-partitioned topics
+		if i := strings.LastIndex(topic, utils.PARTITIONEDTOPICSUFFIX); i > 0 && isPartitioned[topic[:i]] {
+			continue
+		}
+		e.exportResource(ctx, body, "pulsar_topic", topic)
+	}
+}
+
+func (e *Exporter) exportInstances(ctx context.Context, body *hclwrite.Body, namespace string) {
+	parts := strings.Split(namespace, "/")
+	if len(parts) != 2 {
+		return
+	}
+	tenant, ns := parts[0], parts[1]
+
+	lists := []struct {
+		resourceType string
+		list         func(tenant, namespace string) ([]string, error)
+	}{
+		{"pulsar_function", e.clients.V3Client.Functions().GetFunctions},
+		{"pulsar_sink", e.clients.V3Client.Sinks().ListSinks},
+		{"pulsar_source", e.clients.V3Client.Sources().ListSources},
+	}
+
+	for _, l := range lists {
+		names, err := l.list(tenant, ns)
+		if err != nil {
+			e.fail(fmt.Errorf("ERROR_LIST_INSTANCES %s %s: %w", l.resourceType, namespace, err))
+			continue
+		}
+		for _, name := range names {
+			e.exportResource(ctx, body, l.resourceType, namespace+"/"+name)
+		}
+	}
+}
+
+// exportResource imports the object with the importer of its resource and appends its configuration
+func (e *Exporter) exportResource(ctx context.Context, body *hclwrite.Body, resourceType, id string) {
+	r, ok := e.provider.ResourcesMap[resourceType]
+	if !ok || r.Importer == nil {
+		e.fail(fmt.Errorf("ERROR_EXPORT %s: the resource cannot be imported", resourceType))
+		return
+	}
+
+	d := r.Data(nil)
+	d.SetId(id)
+	states, err := r.Importer.StateContext(ctx, d, e.provider.Meta())
+	if err != nil {
+		e.fail(fmt.Errorf("ERROR_EXPORT %s %s: %w", resourceType, id, err))
+		return
+	}
+
+	for _, state := range states {
+		if state.Id() == "" {
+			continue
+		}
+		label := e.uniqueLabel(resourceType, state.Id())
+
+		file := hclwrite.NewEmptyFile()
+		appendResource(file.Body(), resourceType, label, state.Id(), r, state)
+		if err := e.validateConfiguration(ctx, file.Bytes()); err != nil {
+			e.fail(fmt.Errorf("ERROR_INCOMPLETE_CONFIGURATION %s.%s: %w", resourceType, label, err))
+			appendComment(body, fmt.Sprintf("%s.%s cannot be planned, fill in the values which could not be "+
+				"read from the cluster:\n%v", resourceType, label, err))
+		}
+		body.AppendUnstructuredTokens(file.BuildTokens(nil))
+	}
+}
+
+// uniqueLabel suffixes the labels which sanitize to the name of an already exported resource
+func (e *Exporter) uniqueLabel(resourceType, id string) string {
+	base := resourceLabel(id)
+	label := base
+	for i := 2; e.labels[resourceType+"."+label]; i++ {
+		label = fmt.Sprintf("%s_%d", base, i)
+	}
+	e.labels[resourceType+"."+label] = true
+	return label
+}
+
+func (e *Exporter) fail(err error) {
+	e.errs = multierror.Append(e.errs, err)
+}
@@ -0,0 +1,284 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	"github.com/streamnative/terraform-provider-pulsar/pulsar"
+)
+
+func TestResourceLabel(t *testing.T) {
+	cases := map[string]string{
+		"public":                                   "public",
+		"public/default":                           "public_default",
+		"persistent://public/default/orders":       "public_default_orders",
+		"non-persistent://public/default/orders":   "non-persistent_public_default_orders",
+		"public/default/orders.v1":                 "public_default_orders_v1",
+		"1st-tenant/ns":                            "_1st-tenant_ns",
+		"-tenant":                                  "_-tenant",
+		"persistent://public/default/orders@2023!": "public_default_orders_2023",
+	}
+
+	for id, expected := range cases {
+		if label := resourceLabel(id); label != expected {
+			t.Errorf("resourceLabel(%q) = %q, expected %q", id, label, expected)
+		}
+	}
+}
+
+func TestUniqueLabel(t *testing.T) {
+	e := &Exporter{labels: make(map[string]bool)}
+
+	if label := e.uniqueLabel("pulsar_topic", "public/default/a.b"); label != "public_default_a_b" {
+		t.Errorf("unexpected label %q", label)
+	}
+	if label := e.uniqueLabel("pulsar_topic", "public/default/a_b"); label != "public_default_a_b_2" {
+		t.Errorf("unexpected label %q", label)
+	}
+	if label := e.uniqueLabel("pulsar_namespace", "public/default/a_b"); label != "public_default_a_b" {
+		t.Errorf("the labels of different resource types should not collide, got %q", label)
+	}
+}
+
+func TestAppendResource(t *testing.T) {
+	r := pulsar.Provider().ResourcesMap["pulsar_topic"]
+	d := r.Data(nil)
+	d.SetId("persistent://public/default/orders")
+	state := map[string]interface{}{
+		"tenant":     "public",
+		"namespace":  "default",
+		"topic_name": "orders",
+		"topic_type": "persistent",
+		"partitions": 0,
+		"permission_grant": []interface{}{
+			map[string]interface{}{"role": "app", "actions": []interface{}{"produce", "consume"}},
+		},
+		"retention_policies": []interface{}{
+			map[string]interface{}{"retention_time_minutes": 60, "retention_size_mb": 0},
+		},
+		"permission_grant_mode": "additive",
+	}
+	for key, value := range state {
+		if err := d.Set(key, value); err != nil {
+			t.Fatalf("set %s: %v", key, err)
+		}
+	}
+
+	file := hclwrite.NewEmptyFile()
+	appendResource(file.Body(), "pulsar_topic", "public_default_orders", d.Id(), r, d)
+	config := string(file.Bytes())
+
+	for _, expected := range []string{
+		`resource "pulsar_topic" "public_default_orders" {`,
+		`partitions = 0`,
+		`topic_type = "persistent"`,
+		"permission_grant {\n    actions = [\"consume\", \"produce\"]\n    role    = \"app\"\n  }",
+		"retention_size_mb      = 0",
+		"import {\n  to = pulsar_topic.public_default_orders\n  id = \"persistent://public/default/orders\"\n}",
+	} {
+		if !strings.Contains(config, expected) {
+			t.Errorf("expected %q in the configuration:\n%s", expected, config)
+		}
+	}
+
+	for _, omitted := range []string{"permission_grant_mode", "replication_clusters", "offload_policies"} {
+		if strings.Contains(config, omitted) {
+			t.Errorf("expected %s to be omitted from the configuration:\n%s", omitted, config)
+		}
+	}
+
+	if strings.Index(config, "topic_type") > strings.Index(config, "permission_grant") {
+		t.Errorf("expected the attributes to be written before the blocks:\n%s", config)
+	}
+}
+
+func TestRunInvalidFlags(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	if code := Run(context.Background(), []string{"-unknown"}, &stdout, &stderr); code != 2 {
+		t.Errorf("expected the exit code 2, got %d", code)
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("expected no configuration, got %q", stdout.String())
+	}
+}
+
+// newFakeAdminServer serves the given documents by path, a status code is served as an error. The
+// other paths answer 204 like the unset policies of the brokers.
+func newFakeAdminServer(t *testing.T, docs map[string]interface{}) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch doc := docs[r.URL.Path].(type) {
+		case nil:
+			w.WriteHeader(http.StatusNoContent)
+		case int:
+			http.Error(w, `{"reason":"injected"}`, doc)
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(doc)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestExport(t *testing.T) {
+	srv := newFakeAdminServer(t, map[string]interface{}{
+		"/admin/v2/tenants":                                []string{"pulsar", "public", "thanos"},
+		"/admin/v2/tenants/public":                         map[string]interface{}{"allowedClusters": []string{"standalone"}},
+		"/admin/v2/tenants/thanos":                         map[string]interface{}{"allowedClusters": []string{"standalone"}},
+		"/admin/v2/namespaces/pulsar":                      []string{"pulsar/system"},
+		"/admin/v2/namespaces/public":                      []string{"public/default", "public/functions"},
+		"/admin/v2/namespaces/thanos":                      []string{"thanos/infinity", "thanos/broken"},
+		"/admin/v2/persistent/public/functions":            []string{"persistent://public/functions/assignments"},
+		"/admin/v2/persistent/thanos/infinity/partitioned": []string{"persistent://thanos/infinity/gems"},
+		"/admin/v2/persistent/thanos/infinity": []string{
+			"persistent://thanos/infinity/gems-partition-0",
+			"persistent://thanos/infinity/gems-partition-1",
+			"persistent://thanos/infinity/stones",
+			"persistent://thanos/infinity/__change_events",
+		},
+		"/admin/v2/persistent/thanos/infinity/gems/partitions": map[string]int{"partitions": 2},
+		"/admin/v2/clusters":                  []string{"standalone"},
+		"/admin/v3/functions/thanos/infinity": []string{"snap"},
+		"/admin/v3/functions/thanos/infinity/snap": map[string]interface{}{
+			"tenant":      "thanos",
+			"namespace":   "infinity",
+			"name":        "snap",
+			"className":   "org.example.Snap",
+			"jar":         "function://thanos/infinity/snap@v1",
+			"inputs":      []string{"persistent://thanos/infinity/stones"},
+			"parallelism": 1,
+		},
+		"/admin/v3/functions/thanos/broken": http.StatusInternalServerError,
+		"/admin/v3/sinks/thanos/broken":     []string{"gauntlet"},
+		"/admin/v3/sinks/thanos/broken/gauntlet": map[string]interface{}{
+			"tenant":      "thanos",
+			"namespace":   "broken",
+			"name":        "gauntlet",
+			"archive":     "builtin://jdbc-postgres",
+			"inputs":      []string{"persistent://thanos/infinity/stones"},
+			"parallelism": 1,
+		},
+		// the config of the source cannot be read
+		"/admin/v3/sources/thanos/broken": []string{"glove"},
+	})
+
+	provider := pulsar.Provider()
+	diags := provider.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{
+		"web_service_url": srv.URL,
+	}))
+	if diags.HasError() {
+		t.Fatalf("configure the provider: %v", diags)
+	}
+	e, err := New(provider, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	err = e.Export(context.Background(), &out)
+	config := out.String()
+
+	// the objects which cannot be listed are reported, the others are written anyway
+	if err == nil || !strings.Contains(err.Error(), "ERROR_LIST_INSTANCES pulsar_function thanos/broken") {
+		t.Errorf("expected the failed listing to be reported, got %v", err)
+	}
+	// the required values which could not be read are left for the user to fill in
+	if err == nil || strings.Count(err.Error(), "ERROR_INCOMPLETE_CONFIGURATION") != 1 ||
+		!strings.Contains(err.Error(), "ERROR_INCOMPLETE_CONFIGURATION pulsar_source.thanos_broken_glove") {
+		t.Errorf("expected only the source to be reported as incomplete, got %v", err)
+	}
+	for _, expected := range []string{
+		"# pulsar_source.thanos_broken_glove cannot be planned",
+		"# archive could not be read from the cluster, fill it in",
+		"# destination_topic_name could not be read from the cluster, fill it in",
+	} {
+		if !strings.Contains(config, expected) {
+			t.Errorf("expected %q in the configuration:\n%s", expected, config)
+		}
+	}
+
+	for _, expected := range []string{
+		`resource "pulsar_tenant" "public" {`,
+		`resource "pulsar_tenant" "thanos" {`,
+		`resource "pulsar_namespace" "public_default" {`,
+		`resource "pulsar_namespace" "thanos_infinity" {`,
+		`resource "pulsar_namespace" "thanos_broken" {`,
+		`resource "pulsar_topic" "thanos_infinity_gems" {`,
+		`partitions = 2`,
+		`resource "pulsar_topic" "thanos_infinity_stones" {`,
+		`resource "pulsar_function" "thanos_infinity_snap" {`,
+		`resource "pulsar_sink" "thanos_broken_gauntlet" {`,
+		`resource "pulsar_source" "thanos_broken_glove" {`,
+	} {
+		if !strings.Contains(config, expected) {
+			t.Errorf("expected %q in the configuration:\n%s", expected, config)
+		}
+	}
+
+	for _, omitted := range []string{
+		`"pulsar_tenant" "pulsar"`,
+		"pulsar_system",
+		"public_functions",
+		"gems-partition",
+		"gems_partition",
+		"__change_events",
+	} {
+		if strings.Contains(config, omitted) {
+			t.Errorf("expected %s to be omitted from the configuration:\n%s", omitted, config)
+		}
+	}
+
+	// the generated configuration can be planned, apart from the values flagged above
+	file, parseDiags := hclsyntax.ParseConfig(out.Bytes(), "export.tf", hcl.InitialPos)
+	if parseDiags.HasErrors() {
+		t.Fatalf("parse the configuration: %v", parseDiags)
+	}
+	resources := 0
+	for _, block := range file.Body.(*hclsyntax.Body).Blocks {
+		if block.Type != "resource" {
+			continue
+		}
+		resources++
+		err := e.validateResource(context.Background(), block.Labels[0], block.Body)
+		if block.Labels[0] == "pulsar_source" {
+			if err == nil {
+				t.Errorf("expected the incomplete source not to validate")
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s.%s does not validate: %v", block.Labels[0], block.Labels[1], err)
+		}
+	}
+	if resources != 10 {
+		t.Errorf("expected 10 resources, got %d", resources)
+	}
+}
@@ -0,0 +1,209 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package exporter
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+var invalidLabelChars = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// resourceLabel turns an import id into a resource name, e.g. persistent://public/default/orders
+// into public_default_orders
+func resourceLabel(id string) string {
+	label := strings.TrimPrefix(id, "persistent://")
+	label = strings.Trim(invalidLabelChars.ReplaceAllString(label, "_"), "_")
+	if label == "" || (label[0] >= '0' && label[0] <= '9') || label[0] == '-' {
+		label = "_" + label
+	}
+	return label
+}
+
+// appendResource appends the resource block built from the state, followed by its import block
+func appendResource(body *hclwrite.Body, resourceType, label, id string, r *schema.Resource,
+	d *schema.ResourceData) {
+	block := body.AppendNewBlock("resource", []string{resourceType, label})
+	appendAttributes(block.Body(), r.Schema, func(key string) interface{} {
+		return d.Get(key)
+	})
+	body.AppendNewline()
+
+	importBlock := body.AppendNewBlock("import", nil)
+	importBlock.Body().SetAttributeTraversal("to", hcl.Traversal{
+		hcl.TraverseRoot{Name: resourceType},
+		hcl.TraverseAttr{Name: label},
+	})
+	importBlock.Body().SetAttributeValue("id", cty.StringVal(id))
+	body.AppendNewline()
+}
+
+// appendAttributes writes the configurable attributes, then the blocks, of the schema which hold a
+// value. The values equal to the schema default, the sensitive and the deprecated ones are left out,
+// the required ones which could not be read are replaced by a comment.
+func appendAttributes(body *hclwrite.Body, s map[string]*schema.Schema, get func(key string) interface{}) {
+	keys := make([]string, 0, len(s))
+	for key := range s {
+		keys = append(keys, key)
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		iBlock, jBlock := isBlock(s[keys[i]]), isBlock(s[keys[j]])
+		if iBlock != jBlock {
+			return jBlock
+		}
+		return keys[i] < keys[j]
+	})
+
+	written := make(map[string]bool)
+	for _, key := range keys {
+		attr := s[key]
+		if !(attr.Required || attr.Optional) || attr.Sensitive || attr.Deprecated != "" {
+			continue
+		}
+		if conflictsWithAny(attr.ConflictsWith, written) {
+			continue
+		}
+
+		value := get(key)
+		if set, ok := value.(*schema.Set); ok {
+			value = sortedList(set)
+		}
+		if attr.Required && (value == nil || value == "") {
+			appendComment(body, key+" could not be read from the cluster, fill it in")
+			continue
+		}
+		if !attr.Required && isOmitted(attr, value) {
+			continue
+		}
+
+		if elem, ok := attr.Elem.(*schema.Resource); ok {
+			for _, item := range value.([]interface{}) {
+				m, ok := item.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				appendAttributes(body.AppendNewBlock(key, nil).Body(), elem.Schema, func(key string) interface{} {
+					return m[key]
+				})
+			}
+		} else {
+			body.SetAttributeValue(key, ctyValue(value))
+		}
+		written[key] = true
+	}
+}
+
+// appendComment writes a line comment for each line of the text
+func appendComment(body *hclwrite.Body, text string) {
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		body.AppendUnstructuredTokens(hclwrite.Tokens{{
+			Type:  hclsyntax.TokenComment,
+			Bytes: []byte("# " + strings.TrimSpace(line) + "\n"),
+		}})
+	}
+}
+
+func isBlock(attr *schema.Schema) bool {
+	_, ok := attr.Elem.(*schema.Resource)
+	return ok
+}
+
+// sortedList returns the elements of the set, the strings in order to get a stable output
+func sortedList(set *schema.Set) []interface{} {
+	list := set.List()
+	sort.SliceStable(list, func(i, j int) bool {
+		a, aOK := list[i].(string)
+		b, bOK := list[j].(string)
+		return aOK && bOK && a < b
+	})
+	return list
+}
+
+func conflictsWithAny(keys []string, written map[string]bool) bool {
+	for _, key := range keys {
+		if written[key] {
+			return true
+		}
+	}
+	return false
+}
+
+// isOmitted tells if an optional value can be left out of the configuration, the empty strings
+// are the attributes the importer did not read
+func isOmitted(attr *schema.Schema, value interface{}) bool {
+	if value == nil || value == "" {
+		return true
+	}
+	if attr.Default != nil {
+		return fmt.Sprint(value) == fmt.Sprint(attr.Default)
+	}
+
+	switch v := value.(type) {
+	case int:
+		return v == 0
+	case float64:
+		return v == 0
+	case bool:
+		return !v
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+func ctyValue(value interface{}) cty.Value {
+	switch v := value.(type) {
+	case string:
+		return cty.StringVal(v)
+	case int:
+		return cty.NumberIntVal(int64(v))
+	case float64:
+		return cty.NumberFloatVal(v)
+	case bool:
+		return cty.BoolVal(v)
+	case []interface{}:
+		if len(v) == 0 {
+			return cty.EmptyTupleVal
+		}
+		values := make([]cty.Value, 0, len(v))
+		for _, item := range v {
+			values = append(values, ctyValue(item))
+		}
+		return cty.TupleVal(values)
+	case map[string]interface{}:
+		if len(v) == 0 {
+			return cty.EmptyObjectVal
+		}
+		values := make(map[string]cty.Value, len(v))
+		for k, item := range v {
+			values[k] = ctyValue(item)
+		}
+		return cty.ObjectVal(values)
+	}
+	return cty.NullVal(cty.DynamicPseudoType)
+}
@@ -0,0 +1,127 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package exporter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	hclctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// validateConfiguration validates the resources of the generated configuration against the schema and
+// plans their creation, which runs the checks of their CustomizeDiff like terraform plan does
+func (e *Exporter) validateConfiguration(ctx context.Context, src []byte) error {
+	file, diags := hclsyntax.ParseConfig(src, "export.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		return diags
+	}
+
+	for _, block := range file.Body.(*hclsyntax.Body).Blocks {
+		if block.Type != "resource" || len(block.Labels) != 2 {
+			continue
+		}
+		if err := e.validateResource(ctx, block.Labels[0], block.Body); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *Exporter) validateResource(ctx context.Context, resourceType string, body *hclsyntax.Body) error {
+	r, ok := e.provider.ResourcesMap[resourceType]
+	if !ok {
+		return fmt.Errorf("unknown resource type %s", resourceType)
+	}
+
+	src, err := rawConfig(body)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+	raw, err := ctyjson.Unmarshal(b, r.CoreConfigSchema().ImpliedType())
+	if err != nil {
+		return err
+	}
+
+	config := terraform.NewResourceConfigShimmed(raw, r.CoreConfigSchema())
+	if diags := e.provider.ValidateResource(resourceType, config); diags.HasError() {
+		return diagnosticsError(diags)
+	}
+
+	_, err = r.SimpleDiff(ctx, &terraform.InstanceState{RawConfig: raw}, config, e.provider.Meta())
+	if merr, ok := err.(*multierror.Error); ok && len(merr.Errors) == 1 {
+		return merr.Errors[0]
+	}
+	return err
+}
+
+// rawConfig decodes the attributes and the nested blocks of a block body into their JSON form
+func rawConfig(body *hclsyntax.Body) (map[string]interface{}, error) {
+	result := make(map[string]interface{}, len(body.Attributes)+len(body.Blocks))
+
+	for name, attr := range body.Attributes {
+		value, diags := attr.Expr.Value(nil)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		b, err := hclctyjson.Marshal(value, value.Type())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		result[name] = json.RawMessage(b)
+	}
+
+	for _, block := range body.Blocks {
+		nested, err := rawConfig(block.Body)
+		if err != nil {
+			return nil, err
+		}
+		blocks, _ := result[block.Type].([]interface{})
+		result[block.Type] = append(blocks, nested)
+	}
+
+	return result, nil
+}
+
+func diagnosticsError(diags diag.Diagnostics) error {
+	var messages []string
+	for _, d := range diags {
+		if d.Severity != diag.Error {
+			continue
+		}
+		message := d.Summary
+		if d.Detail != "" {
+			message += ": " + d.Detail
+		}
+		messages = append(messages, message)
+	}
+	return fmt.Errorf("%s", strings.Join(messages, "\n"))
+}
@@ -18,8 +18,12 @@
 package main
 
 import (
+	"context"
+	"os"
+
 	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
 
+	"github.com/streamnative/terraform-provider-pulsar/exporter"
 	"github.com/streamnative/terraform-provider-pulsar/pulsar"
 )
 
@@ -27,6 +31,10 @@ import (
 //go:generate go run github.com/hashicorp/terraform-plugin-docs/cmd/tfplugindocs@v0.13.0
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == exporter.CommandName {
+		os.Exit(exporter.Run(context.Background(), os.Args[2:], os.Stdout, os.Stderr))
+	}
+
 	plugin.Serve(&plugin.ServeOpts{
 		ProviderFunc: pulsar.Provider,
 	})
@@ -18,9 +18,12 @@
 package admin
 
 import (
+	"net/http"
+
 	"github.com/apache/pulsar-client-go/oauth2"
 	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/admin"
 	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/admin/auth"
+	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/rest"
 	"github.com/pkg/errors"
 
 	"github.com/streamnative/terraform-provider-pulsar/pkg/authentication"
@@ -28,13 +31,9 @@ import (
 
 func NewPulsarAdminClient(c *PulsarAdminConfig) (admin.Client, error) {
 	if c.AuthenticationType() == authentication.AuthenticationOauth2 {
-		oauth2Provider, err := auth.NewAuthenticationOAuth2WithDefaultFlow(oauth2.Issuer{
-			IssuerEndpoint: c.Config.IssuerEndpoint,
-			ClientID:       c.Config.ClientID,
-			Audience:       c.Config.Audience,
-		}, c.Config.KeyFile)
+		oauth2Provider, err := newOAuth2Provider(c)
 		if err != nil {
-			return nil, errors.Wrapf(err, "failed to create pulsar oauth2 provider")
+			return nil, err
 		}
 
 		client, err := admin.NewPulsarClientWithAuthProvider(c.Config, oauth2Provider)
@@ -52,3 +51,46 @@ func NewPulsarAdminClient(c *PulsarAdminConfig) (admin.Client, error) {
 
 	return client, nil
 }
+
+// NewPulsarRestClient returns a raw REST client sharing the authentication of the admin client,
+// used for the admin endpoints that are not covered by the pulsaradmin library.
+func NewPulsarRestClient(c *PulsarAdminConfig) (*rest.Client, error) {
+	var provider auth.Provider
+	var err error
+
+	if c.AuthenticationType() == authentication.AuthenticationOauth2 {
+		provider, err = newOAuth2Provider(c)
+	} else {
+		provider, err = auth.GetAuthProvider(c.Config)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create pulsar rest client")
+	}
+
+	serviceURL := c.Config.WebServiceURL
+	if len(serviceURL) == 0 {
+		serviceURL = admin.DefaultWebServiceURL
+	}
+
+	return &rest.Client{
+		ServiceURL:  serviceURL,
+		VersionInfo: admin.ReleaseVersion,
+		HTTPClient: &http.Client{
+			Timeout:   admin.DefaultHTTPTimeOutDuration,
+			Transport: provider,
+		},
+	}, nil
+}
+
+func newOAuth2Provider(c *PulsarAdminConfig) (auth.Provider, error) {
+	oauth2Provider, err := auth.NewAuthenticationOAuth2WithDefaultFlow(oauth2.Issuer{
+		IssuerEndpoint: c.Config.IssuerEndpoint,
+		ClientID:       c.Config.ClientID,
+		Audience:       c.Config.Audience,
+	}, c.Config.KeyFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create pulsar oauth2 provider")
+	}
+
+	return oauth2Provider, nil
+}
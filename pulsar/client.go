@@ -18,7 +18,11 @@
 package pulsar
 
 import (
+	"path"
+
 	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/admin"
+	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/rest"
+	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/utils"
 )
 
 func getClientFromMeta(meta interface{}) admin.Client {
@@ -28,3 +32,22 @@ func getClientFromMeta(meta interface{}) admin.Client {
 func getV3ClientFromMeta(meta interface{}) admin.Client {
 	return meta.(PulsarClientBundle).V3Client
 }
+
+func getRestClientFromMeta(meta interface{}) *rest.Client {
+	return meta.(PulsarClientBundle).RestClient
+}
+
+// clusterRestEndpoint builds the v2 admin endpoint of a cluster, e.g. /admin/v2/clusters/standalone
+func clusterRestEndpoint(cluster string, parts ...string) string {
+	return path.Join(append([]string{utils.MakeHTTPPath("v2", "/clusters"), cluster}, parts...)...)
+}
+
+// namespaceRestEndpoint builds the v2 admin endpoint of a namespace, e.g. /admin/v2/namespaces/t/ns/offloadPolicies
+func namespaceRestEndpoint(ns *utils.NameSpaceName, parts ...string) string {
+	return path.Join(append([]string{utils.MakeHTTPPath("v2", "/namespaces"), ns.String()}, parts...)...)
+}
+
+// topicRestEndpoint builds the v2 admin endpoint of a topic, e.g. /admin/v2/persistent/t/ns/topic/offloadPolicies
+func topicRestEndpoint(topic *utils.TopicName, parts ...string) string {
+	return path.Join(append([]string{utils.MakeHTTPPath("v2", "/"), topic.GetRestPath()}, parts...)...)
+}
@@ -0,0 +1,172 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsar
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// connectorConfigsKeys names the attributes holding the configs of a sink or a source, the configs
+// are given either as a JSON string or as a map, and the sensitive configs are merged into them
+type connectorConfigsKeys struct {
+	configs          string
+	configsMap       string
+	sensitiveConfigs string
+}
+
+func schemaConnectorConfigs(keys connectorConfigsKeys, descriptions map[string]string) map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		keys.configs: {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Description:      descriptions[keys.configs],
+			ConflictsWith:    []string{keys.configsMap},
+			ValidateFunc:     jsonValidateFunc,
+			DiffSuppressFunc: jsonDiffSuppressFunc,
+		},
+		keys.configsMap: {
+			Type:          schema.TypeMap,
+			Optional:      true,
+			Description:   descriptions[keys.configsMap],
+			ConflictsWith: []string{keys.configs},
+			Elem:          &schema.Schema{Type: schema.TypeString},
+		},
+		keys.sensitiveConfigs: {
+			Type:        schema.TypeMap,
+			Optional:    true,
+			Sensitive:   true,
+			Description: descriptions[keys.sensitiveConfigs],
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+	}
+}
+
+// customizeDiffConnectorConfigs rejects sensitive configs that are also given as plain configs, they
+// would be hidden from the configs read back and never converge
+func customizeDiffConnectorConfigs(keys connectorConfigsKeys) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		sensitive := d.Get(keys.sensitiveConfigs).(map[string]interface{})
+		if len(sensitive) != 0 {
+			plain := d.Get(keys.configsMap).(map[string]interface{})
+			if configsJSON := d.Get(keys.configs).(string); len(plain) == 0 && configsJSON != "" &&
+				d.NewValueKnown(keys.configs) {
+				if err := json.Unmarshal([]byte(configsJSON), &plain); err != nil {
+					return fmt.Errorf("ERROR_UNMARSHAL_CONFIGS: %w", err)
+				}
+			}
+			for key := range sensitive {
+				if _, ok := plain[key]; ok {
+					return fmt.Errorf("ERROR_DUPLICATE_CONFIG_KEY: %s is set in both %s and %s", key,
+						keys.sensitiveConfigs, keys.configs)
+				}
+			}
+		}
+
+		return nil
+	}
+}
+
+// marshalConnectorConfigs merges the sensitive configs into the configs given as a map or a JSON string
+func marshalConnectorConfigs(d *schema.ResourceData, keys connectorConfigsKeys) (map[string]interface{}, error) {
+	var configs map[string]interface{}
+
+	if inter, ok := d.GetOk(keys.configsMap); ok {
+		configs = inter.(map[string]interface{})
+	} else if inter, ok := d.GetOk(keys.configs); ok {
+		configsJSON := inter.(string)
+		if err := json.Unmarshal([]byte(configsJSON), &configs); err != nil {
+			return nil, fmt.Errorf("cannot unmarshal the configs: %s: %w", configsJSON, err)
+		}
+	}
+
+	if inter, ok := d.GetOk(keys.sensitiveConfigs); ok {
+		if configs == nil {
+			configs = make(map[string]interface{})
+		}
+		for key, value := range inter.(map[string]interface{}) {
+			configs[key] = value
+		}
+	}
+
+	return configs, nil
+}
+
+// flattenConnectorConfigs sets the configs read from the worker without the sensitive ones, which are
+// kept as configured since they cannot be told apart from drift without showing them. The configs are
+// read into the attribute which is configured, or which holds them in the state on refresh, and none
+// of them is set otherwise.
+func flattenConnectorConfigs(d *schema.ResourceData, keys connectorConfigsKeys, configs map[string]interface{}) error {
+	_, configsMapSet := d.GetOk(keys.configsMap)
+	_, configsSet := d.GetOk(keys.configs)
+	// the config is only known on apply
+	if raw := d.GetRawConfig(); !raw.IsNull() {
+		configsMapSet = !raw.GetAttr(keys.configsMap).IsNull()
+		configsSet = !raw.GetAttr(keys.configs).IsNull()
+	}
+
+	return setConnectorConfigs(d, keys, configs, configsSet, configsMapSet)
+}
+
+// importConnectorConfigs reads all the configs into the JSON string, nothing is configured yet on import
+func importConnectorConfigs(d *schema.ResourceData, keys connectorConfigsKeys, configs map[string]interface{}) error {
+	return setConnectorConfigs(d, keys, configs, true, false)
+}
+
+func setConnectorConfigs(d *schema.ResourceData, keys connectorConfigsKeys, configs map[string]interface{},
+	configsSet, configsMapSet bool) error {
+	sensitive := d.Get(keys.sensitiveConfigs).(map[string]interface{})
+
+	visible := make(map[string]interface{}, len(configs))
+	for key, value := range configs {
+		if _, ok := sensitive[key]; !ok {
+			visible[key] = value
+		}
+	}
+
+	configsJSON := ""
+	if len(visible) != 0 && configsSet {
+		b, err := json.Marshal(visible)
+		if err != nil {
+			return fmt.Errorf("cannot marshal configs: %w", err)
+		}
+		configsJSON = string(b)
+	}
+	if err := d.Set(keys.configs, configsJSON); err != nil {
+		return err
+	}
+
+	configsMap := make(map[string]interface{}, len(visible))
+	if configsMapSet {
+		for key, value := range visible {
+			if s, ok := value.(string); ok {
+				configsMap[key] = s
+				continue
+			}
+			b, err := json.Marshal(value)
+			if err != nil {
+				return fmt.Errorf("cannot marshal config %s: %w", key, err)
+			}
+			configsMap[key] = string(b)
+		}
+	}
+	return d.Set(keys.configsMap, configsMap)
+}
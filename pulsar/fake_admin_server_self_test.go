@@ -0,0 +1,170 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsar
+
+import (
+	"net/http"
+	"testing"
+
+	common "github.com/apache/pulsar-client-go/pulsaradmin/pkg/admin/config"
+	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/rest"
+	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/utils"
+)
+
+func TestFakeAdminServerNamespaces(t *testing.T) {
+	fake := newFakeAdminServer(t)
+	client, err := sharedClientWithVersion(fake.URL, common.V2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.Namespaces().CreateNamespace("missing/ns"); !isFakeAdminStatus(err, http.StatusNotFound) {
+		t.Fatalf("expected a 404 for a namespace of a missing tenant, got %v", err)
+	}
+	if err := client.Namespaces().CreateNamespace("public/test"); err != nil {
+		t.Fatal(err)
+	}
+
+	retention := utils.NewRetentionPolicies(60, 100)
+	if err := client.Namespaces().SetRetention("public/test", retention); err != nil {
+		t.Fatal(err)
+	}
+	got, err := client.Namespaces().GetRetention("public/test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *got != retention {
+		t.Fatalf("expected the retention %v, got %v", retention, *got)
+	}
+
+	ns, _ := utils.GetNamespaceName("public/test")
+	if err := client.Namespaces().GrantNamespacePermission(*ns, "app",
+		[]utils.AuthAction{"produce"}); err != nil {
+		t.Fatal(err)
+	}
+	permissions, err := client.Namespaces().GetNamespacePermissions(*ns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(permissions["app"]) != 1 || permissions["app"][0] != "produce" {
+		t.Fatalf("expected the produce permission of app, got %v", permissions)
+	}
+
+	topic, _ := utils.GetTopicName("persistent://public/test/orders")
+	if err := client.Topics().Create(*topic, 3); err != nil {
+		t.Fatal(err)
+	}
+	partitioned, nonPartitioned, err := client.Topics().List(*ns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(partitioned) != 1 || len(nonPartitioned) != 0 {
+		t.Fatalf("expected one partitioned topic, got %v and %v", partitioned, nonPartitioned)
+	}
+
+	if err := client.Namespaces().DeleteNamespace("public/test"); !isFakeAdminStatus(err, http.StatusConflict) {
+		t.Fatalf("expected a 409 when deleting a namespace with topics, got %v", err)
+	}
+	fake.remove("/admin/v2/persistent/public/test/orders")
+	if err := client.Namespaces().DeleteNamespace("public/test"); err != nil {
+		t.Fatal(err)
+	}
+	if fake.exists("/admin/v2/namespaces/public/test/retention") {
+		t.Fatal("expected the policies to be deleted with the namespace")
+	}
+}
+
+func TestFakeAdminServerFunctions(t *testing.T) {
+	fake := newFakeAdminServer(t)
+	client, err := sharedClientWithVersion(fake.URL, common.V3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := &utils.FunctionConfig{
+		Tenant:      "public",
+		Namespace:   "default",
+		Name:        "echo",
+		ClassName:   "org.apache.pulsar.functions.api.examples.ExclamationFunction",
+		Parallelism: 2,
+	}
+	if err := client.Functions().CreateFuncWithURL(config, "function://public/default/echo@v1"); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := client.Functions().GetFunction("public", "default", "echo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ClassName != config.ClassName || got.Parallelism != 2 {
+		t.Fatalf("expected the created function, got %+v", got)
+	}
+
+	status, err := client.Functions().GetFunctionStatus("public", "default", "echo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.NumInstances != 2 || status.NumRunning != 2 {
+		t.Fatalf("expected 2 running instances, got %+v", status)
+	}
+
+	if err := client.Functions().StopFunction("public", "default", "echo"); err != nil {
+		t.Fatal(err)
+	}
+	fake.setInstanceError("/admin/v3/functions/public/default/echo", "boom")
+	status, err = client.Functions().GetFunctionStatus("public", "default", "echo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.NumRunning != 0 || status.Instances[0].Status.Err != "boom" {
+		t.Fatalf("expected stopped instances failing with boom, got %+v", status)
+	}
+
+	if err := client.Functions().DeleteFunction("public", "default", "echo"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Functions().GetFunction("public", "default", "echo"); !isFakeAdminStatus(err,
+		http.StatusNotFound) {
+		t.Fatalf("expected a 404 for a deleted function, got %v", err)
+	}
+}
+
+func TestFakeAdminServerInjectError(t *testing.T) {
+	fake := newFakeAdminServer(t)
+	client, err := sharedClientWithVersion(fake.URL, common.V2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fake.injectError(http.MethodGet, "/admin/v2/tenants", http.StatusServiceUnavailable, 1)
+	if _, err := client.Tenants().List(); !isFakeAdminStatus(err, http.StatusServiceUnavailable) {
+		t.Fatalf("expected the injected 503, got %v", err)
+	}
+	tenants, err := client.Tenants().List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tenants) != 1 || tenants[0] != "public" {
+		t.Fatalf("expected the public tenant once the failure is consumed, got %v", tenants)
+	}
+}
+
+func isFakeAdminStatus(err error, status int) bool {
+	cliErr, ok := err.(rest.Error)
+	return ok && cliErr.Code == status
+}
@@ -0,0 +1,829 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsar
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// fakeAdminEntityPatterns match the admin endpoints of the entities held by the fake admin server, any
+// path below an existing entity is one of its policies, e.g. /admin/v2/namespaces/t/ns/retention
+var fakeAdminEntityPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`^/admin/v2/clusters/[^/]+`),
+	regexp.MustCompile(`^/admin/v2/tenants/[^/]+`),
+	regexp.MustCompile(`^/admin/v2/namespaces/[^/]+/[^/]+`),
+	regexp.MustCompile(`^/admin/v2/(persistent|non-persistent)/[^/]+/[^/]+/[^/]+`),
+	regexp.MustCompile(`^/admin/v3/(functions|sinks|sources)/[^/]+/[^/]+/[^/]+`),
+}
+
+var (
+	fakeAdminTopicListPattern    = regexp.MustCompile(`^/admin/v2/(persistent|non-persistent)/([^/]+)/([^/]+)(/partitioned)?$`)
+	fakeAdminInstanceListPattern = regexp.MustCompile(`^/admin/v3/(functions|sinks|sources)/([^/]+)/([^/]+)$`)
+	fakeAdminNamespaceListPath   = regexp.MustCompile(`^/admin/v2/namespaces/([^/]+)$`)
+)
+
+// fakeAdminServer is an in-memory fake of the admin v2/v3 REST endpoints of tenants, clusters,
+// namespaces, topics, functions, sinks and sources. It holds the documents posted by the provider,
+// so the resources can be unit tested without a broker, and lets tests change that state behind the
+// back of terraform or inject errors.
+type fakeAdminServer struct {
+	*httptest.Server
+
+	mu             sync.Mutex
+	docs           map[string]json.RawMessage
+	stopped        map[string]bool
+	instanceErrors map[string]string
+	failures       []*fakeAdminFailure
+}
+
+type fakeAdminFailure struct {
+	method string
+	path   string
+	status int
+	// the number of requests still failing, the failure is permanent when it is not positive
+	times int
+}
+
+// newFakeAdminServer starts a fake admin server seeded like a standalone cluster, with the standalone
+// cluster, the public tenant and the public/default namespace
+func newFakeAdminServer(t *testing.T) *fakeAdminServer {
+	f := &fakeAdminServer{
+		docs:           make(map[string]json.RawMessage),
+		stopped:        make(map[string]bool),
+		instanceErrors: make(map[string]string),
+	}
+	f.Server = httptest.NewServer(f)
+	t.Cleanup(f.Close)
+
+	f.put("/admin/v2/clusters/standalone", map[string]interface{}{
+		"serviceUrl":       "http://localhost:8080",
+		"brokerServiceUrl": "pulsar://localhost:6650",
+	})
+	f.put("/admin/v2/tenants/public", map[string]interface{}{
+		"adminRoles":      []string{},
+		"allowedClusters": []string{"standalone"},
+	})
+	f.put("/admin/v2/namespaces/public/default", map[string]interface{}{})
+
+	return f
+}
+
+// testUnitPreCheck skips the unit tests driving terraform when its binary cannot be found, the binary
+// is not downloaded on the fly to keep the unit tests offline
+func testUnitPreCheck(t *testing.T) {
+	if os.Getenv("TF_ACC_TERRAFORM_PATH") != "" {
+		return
+	}
+	if _, err := exec.LookPath("terraform"); err != nil {
+		t.Skip("the terraform binary is required, set TF_ACC_TERRAFORM_PATH or add terraform to the PATH")
+	}
+}
+
+func testFakeProvider(url string) string {
+	return fmt.Sprintf(`
+provider "pulsar" {
+  web_service_url = "%s"
+}
+`, url)
+}
+
+// checkDestroyed verifies that the entities of the resources of the given type are gone from the fake
+func (f *fakeAdminServer) checkDestroyed(resourceType string, entityPath func(id string) string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type != resourceType {
+				continue
+			}
+			if p := entityPath(rs.Primary.ID); f.exists(p) {
+				return fmt.Errorf("ERROR_RESOURCE_STILL_EXISTS: %s", p)
+			}
+		}
+		return nil
+	}
+}
+
+// put stores a document, e.g. to change a policy outside of terraform
+func (f *fakeAdminServer) put(path string, value interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		panic(err)
+	}
+	f.docs[path] = b
+}
+
+// get decodes a stored document, it returns false when there is none
+func (f *fakeAdminServer) get(path string, out interface{}) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	doc, ok := f.docs[path]
+	if !ok {
+		return false
+	}
+	if err := json.Unmarshal(doc, out); err != nil {
+		panic(err)
+	}
+	return true
+}
+
+func (f *fakeAdminServer) exists(path string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, ok := f.docs[path]
+	return ok
+}
+
+// remove deletes a document together with the documents below it, e.g. to delete an entity outside
+// of terraform
+func (f *fakeAdminServer) remove(path string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.removeLocked(path)
+}
+
+// injectError fails the requests matching the method and path with the given status, the requests
+// fail forever when times is not positive
+func (f *fakeAdminServer) injectError(method, path string, status, times int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.failures = append(f.failures, &fakeAdminFailure{method: method, path: path, status: status, times: times})
+}
+
+// setInstanceError makes the instances of a function, sink or source fail with the given error
+func (f *fakeAdminServer) setInstanceError(path, instanceErr string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.instanceErrors[path] = instanceErr
+}
+
+func (f *fakeAdminServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeFakeAdminError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	p := path.Clean(r.URL.Path)
+
+	if status := f.injectedFailure(r.Method, p); status != 0 {
+		writeFakeAdminError(w, status, "injected failure")
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		if list, ok := f.list(p); ok {
+			writeFakeAdminJSON(w, list)
+			return
+		}
+	}
+
+	entity, sub := splitFakeAdminPath(p)
+	switch {
+	case entity == "":
+		writeFakeAdminError(w, http.StatusNotFound, "unknown endpoint")
+	case strings.HasPrefix(entity, "/admin/v3/"):
+		f.serveInstances(w, r, entity, sub, body)
+	case strings.Contains(entity, "persistent/") && (sub == "" || sub == "partitions"):
+		f.serveTopic(w, r, entity, sub, body)
+	case sub == "":
+		f.serveEntity(w, r, entity, body)
+	default:
+		f.servePolicy(w, r, entity, sub, body)
+	}
+}
+
+func (f *fakeAdminServer) injectedFailure(method, p string) int {
+	for i, failure := range f.failures {
+		if (failure.method != "" && failure.method != method) || failure.path != p {
+			continue
+		}
+		if failure.times > 0 {
+			failure.times--
+			if failure.times == 0 {
+				f.failures = append(f.failures[:i], f.failures[i+1:]...)
+			}
+		}
+		return failure.status
+	}
+	return 0
+}
+
+func (f *fakeAdminServer) list(p string) (interface{}, bool) {
+	switch {
+	case p == "/admin/v2/clusters" || p == "/admin/v2/tenants":
+		return f.children(p+"/", func(name string, _ json.RawMessage) (string, bool) {
+			return name, true
+		}), true
+	case fakeAdminNamespaceListPath.MatchString(p):
+		tenant := fakeAdminNamespaceListPath.FindStringSubmatch(p)[1]
+		return f.children(p+"/", func(name string, _ json.RawMessage) (string, bool) {
+			return tenant + "/" + name, true
+		}), true
+	case fakeAdminTopicListPattern.MatchString(p):
+		m := fakeAdminTopicListPattern.FindStringSubmatch(p)
+		partitioned := m[4] != ""
+		prefix := fmt.Sprintf("/admin/v2/%s/%s/%s/", m[1], m[2], m[3])
+		return f.children(prefix, func(name string, doc json.RawMessage) (string, bool) {
+			var metadata struct {
+				Partitions int `json:"partitions"`
+			}
+			_ = json.Unmarshal(doc, &metadata)
+			return fmt.Sprintf("%s://%s/%s/%s", m[1], m[2], m[3], name), (metadata.Partitions > 0) == partitioned
+		}), true
+	case fakeAdminInstanceListPattern.MatchString(p):
+		return f.children(p+"/", func(name string, _ json.RawMessage) (string, bool) {
+			return name, true
+		}), true
+	}
+	return nil, false
+}
+
+// children lists the entities directly below the prefix
+func (f *fakeAdminServer) children(prefix string, accept func(name string, doc json.RawMessage) (string,
+	bool)) []string {
+	result := make([]string, 0)
+	for key, doc := range f.docs {
+		name := strings.TrimPrefix(key, prefix)
+		if !strings.HasPrefix(key, prefix) || strings.Contains(name, "/") {
+			continue
+		}
+		if entity, _ := splitFakeAdminPath(key); entity != key {
+			continue
+		}
+		if value, ok := accept(name, doc); ok {
+			result = append(result, value)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// serveEntity handles tenants, clusters and namespaces
+func (f *fakeAdminServer) serveEntity(w http.ResponseWriter, r *http.Request, entity string, body []byte) {
+	_, exists := f.docs[entity]
+
+	switch r.Method {
+	case http.MethodPut:
+		if exists {
+			writeFakeAdminError(w, http.StatusConflict, "already exists")
+			return
+		}
+		if parent := fakeAdminParent(entity); parent != "" && f.docs[parent] == nil {
+			writeFakeAdminError(w, http.StatusNotFound, parent+" does not exist")
+			return
+		}
+		f.docs[entity] = fakeAdminDocument(body)
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodPost:
+		if !exists {
+			writeFakeAdminError(w, http.StatusNotFound, "does not exist")
+			return
+		}
+		f.docs[entity] = fakeAdminDocument(body)
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodGet:
+		if !exists {
+			writeFakeAdminError(w, http.StatusNotFound, "does not exist")
+			return
+		}
+		if strings.HasPrefix(entity, "/admin/v2/namespaces/") {
+			writeFakeAdminJSON(w, f.namespacePolicies(entity))
+			return
+		}
+		writeFakeAdminRaw(w, f.docs[entity])
+	case http.MethodDelete:
+		if !exists {
+			writeFakeAdminError(w, http.StatusNotFound, "does not exist")
+			return
+		}
+		if f.hasChildEntities(entity) {
+			writeFakeAdminError(w, http.StatusConflict, "not empty")
+			return
+		}
+		f.removeLocked(entity)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeFakeAdminError(w, http.StatusMethodNotAllowed, r.Method)
+	}
+}
+
+// namespacePolicies reflects the permissions granted with their own endpoints in the namespace policies,
+// including the grants of its topics
+func (f *fakeAdminServer) namespacePolicies(entity string) map[string]interface{} {
+	policies := make(map[string]interface{})
+	_ = json.Unmarshal(f.docs[entity], &policies)
+
+	subscriptionRoles := make(map[string]json.RawMessage)
+	prefix := entity + "/permissions/subscription/"
+	for key, doc := range f.docs {
+		if strings.HasPrefix(key, prefix) {
+			subscriptionRoles[strings.TrimPrefix(key, prefix)] = doc
+		}
+	}
+	// the topic grants are stored in the namespace policies by topic name
+	destinationAuth := make(map[string]map[string]json.RawMessage)
+	namespace := strings.TrimPrefix(entity, "/admin/v2/namespaces/")
+	for _, domain := range []string{"persistent", "non-persistent"} {
+		for _, topic := range f.children("/admin/v2/"+domain+"/"+namespace+"/", func(name string,
+			_ json.RawMessage) (string, bool) {
+			return name, true
+		}) {
+			key := "/admin/v2/" + domain + "/" + namespace + "/" + topic + "/permissions"
+			if grants := f.collection(key); len(grants) > 0 {
+				destinationAuth[domain+"://"+namespace+"/"+topic] = grants
+			}
+		}
+	}
+	policies["auth_policies"] = map[string]interface{}{
+		"namespace_auth":          f.collection(entity + "/permissions"),
+		"destination_auth":        destinationAuth,
+		"subscription_auth_roles": subscriptionRoles,
+	}
+
+	return policies
+}
+
+// serveTopic handles the creation, the metadata and the deletion of partitioned and non-partitioned topics
+func (f *fakeAdminServer) serveTopic(w http.ResponseWriter, r *http.Request, entity, sub string, body []byte) {
+	_, exists := f.docs[entity]
+
+	switch r.Method {
+	case http.MethodPut:
+		if exists {
+			writeFakeAdminError(w, http.StatusConflict, "already exists")
+			return
+		}
+		if f.docs[fakeAdminParent(entity)] == nil {
+			writeFakeAdminError(w, http.StatusNotFound, "namespace does not exist")
+			return
+		}
+		partitions := 0
+		if sub == "partitions" {
+			_ = json.Unmarshal(body, &partitions)
+		}
+		f.docs[entity], _ = json.Marshal(map[string]int{"partitions": partitions})
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodPost:
+		if !exists {
+			writeFakeAdminError(w, http.StatusNotFound, "topic does not exist")
+			return
+		}
+		var partitions int
+		_ = json.Unmarshal(body, &partitions)
+		f.docs[entity], _ = json.Marshal(map[string]int{"partitions": partitions})
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodGet:
+		if !exists {
+			writeFakeAdminError(w, http.StatusNotFound, "topic does not exist")
+			return
+		}
+		writeFakeAdminRaw(w, f.docs[entity])
+	case http.MethodDelete:
+		if !exists {
+			writeFakeAdminError(w, http.StatusNotFound, "topic does not exist")
+			return
+		}
+		f.removeLocked(entity)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeFakeAdminError(w, http.StatusMethodNotAllowed, r.Method)
+	}
+}
+
+// servePolicy handles the endpoints below an entity, which hold a document each
+func (f *fakeAdminServer) servePolicy(w http.ResponseWriter, r *http.Request, entity, sub string, body []byte) {
+	if _, exists := f.docs[entity]; !exists {
+		writeFakeAdminError(w, http.StatusNotFound, "does not exist")
+		return
+	}
+
+	key := entity + "/" + sub
+	switch {
+	case sub == "removeOffloadPolicies":
+		key = entity + "/offloadPolicies"
+	case sub == "backlogQuota":
+		f.serveBacklogQuota(w, r, entity, body)
+		return
+	case sub == "stats" || sub == "partitioned-stats":
+		writeFakeAdminJSON(w, map[string]interface{}{"subscriptions": f.collection(entity + "/subscription")})
+		return
+	case strings.HasPrefix(sub, "subscription/") && strings.HasSuffix(sub, "/replicatedSubscriptionStatus"):
+		f.serveReplicatedSubscriptionStatus(w, r, entity+"/"+path.Dir(sub), body)
+		return
+	case sub == "peers" && strings.HasPrefix(entity, "/admin/v2/clusters/"):
+		f.servePeerClusters(w, r, entity, body)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut, http.MethodPost:
+		f.docs[key] = fakeAdminDocument(body)
+		if sub == "offloadPolicies" {
+			f.docs[key] = fakeAdminOffloadDefaults(f.docs[key])
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodGet:
+		doc, ok := f.docs[key]
+		switch {
+		case sub == "permissions" && strings.Contains(entity, "persistent/"):
+			// the brokers merge the namespace grants into the grants of the topic
+			grants := f.collection(fakeAdminParent(entity) + "/permissions")
+			for role, actions := range f.collection(key) {
+				grants[role] = actions
+			}
+			writeFakeAdminJSON(w, grants)
+		case ok && sub == "antiAffinity":
+			// the anti affinity group is returned as plain text
+			var group string
+			_ = json.Unmarshal(doc, &group)
+			_, _ = w.Write([]byte(group))
+		case ok:
+			writeFakeAdminRaw(w, doc)
+		case len(f.collection(key)) != 0:
+			writeFakeAdminJSON(w, f.collection(key))
+		default:
+			// the policy is not set
+			w.WriteHeader(http.StatusNoContent)
+		}
+	case http.MethodDelete:
+		f.removeLocked(key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeFakeAdminError(w, http.StatusMethodNotAllowed, r.Method)
+	}
+}
+
+// serveReplicatedSubscriptionStatus updates the replication of an existing subscription, the subscriptions
+// are created with fake.put
+func (f *fakeAdminServer) serveReplicatedSubscriptionStatus(w http.ResponseWriter, r *http.Request, subscription string,
+	body []byte) {
+	if _, exists := f.docs[subscription]; !exists {
+		writeFakeAdminError(w, http.StatusNotFound, "subscription not found")
+		return
+	}
+	if r.Method != http.MethodPost {
+		writeFakeAdminError(w, http.StatusMethodNotAllowed, r.Method)
+		return
+	}
+
+	var replicated bool
+	_ = json.Unmarshal(body, &replicated)
+	f.docs[subscription], _ = json.Marshal(map[string]bool{"isReplicated": replicated})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// servePeerClusters reads and updates the peer clusters held in the cluster data, the peers are
+// validated like the brokers do
+func (f *fakeAdminServer) servePeerClusters(w http.ResponseWriter, r *http.Request, entity string, body []byte) {
+	cluster := make(map[string]interface{})
+	_ = json.Unmarshal(f.docs[entity], &cluster)
+
+	switch r.Method {
+	case http.MethodGet:
+		writeFakeAdminJSON(w, cluster["peerClusterNames"])
+	case http.MethodPost:
+		var peers []string
+		_ = json.Unmarshal(body, &peers)
+		for _, peer := range peers {
+			if "/admin/v2/clusters/"+peer == entity {
+				writeFakeAdminError(w, http.StatusPreconditionFailed, peer+" itself can't be part of peer-list")
+				return
+			}
+			if f.docs["/admin/v2/clusters/"+peer] == nil {
+				writeFakeAdminError(w, http.StatusPreconditionFailed, "Peer cluster "+peer+" doesn't exist")
+				return
+			}
+		}
+		cluster["peerClusterNames"] = peers
+		f.docs[entity], _ = json.Marshal(cluster)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeFakeAdminError(w, http.StatusMethodNotAllowed, r.Method)
+	}
+}
+
+// serveBacklogQuota stores the quotas posted per type in the backlog quota map
+func (f *fakeAdminServer) serveBacklogQuota(w http.ResponseWriter, r *http.Request, entity string, body []byte) {
+	quotaType := r.URL.Query().Get("backlogQuotaType")
+	if quotaType == "" {
+		quotaType = "destination_storage"
+	}
+
+	quotas := make(map[string]json.RawMessage)
+	_ = json.Unmarshal(f.docs[entity+"/backlogQuotaMap"], &quotas)
+
+	switch r.Method {
+	case http.MethodPost:
+		quotas[quotaType] = fakeAdminDocument(body)
+	case http.MethodDelete:
+		delete(quotas, quotaType)
+	default:
+		writeFakeAdminError(w, http.StatusMethodNotAllowed, r.Method)
+		return
+	}
+
+	f.docs[entity+"/backlogQuotaMap"], _ = json.Marshal(quotas)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// serveInstances handles functions, sinks and sources, their configuration is posted as a multipart form
+func (f *fakeAdminServer) serveInstances(w http.ResponseWriter, r *http.Request, entity, sub string,
+	body []byte) {
+	_, exists := f.docs[entity]
+	if !exists && !(sub == "" && r.Method == http.MethodPost) {
+		writeFakeAdminError(w, http.StatusNotFound, "does not exist")
+		return
+	}
+
+	switch {
+	case sub == "" && r.Method == http.MethodPost:
+		if exists {
+			writeFakeAdminError(w, http.StatusBadRequest, "already exists")
+			return
+		}
+		if f.docs[fakeAdminParent(entity)] == nil {
+			writeFakeAdminError(w, http.StatusBadRequest, "namespace does not exist")
+			return
+		}
+		config, err := fakeAdminInstanceConfig(r, body)
+		if err != nil {
+			writeFakeAdminError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		f.docs[entity] = fakeAdminWorkerDefaults(entity, config)
+		delete(f.stopped, entity)
+		w.WriteHeader(http.StatusNoContent)
+	case sub == "" && r.Method == http.MethodPut:
+		config, err := fakeAdminInstanceConfig(r, body)
+		if err != nil {
+			writeFakeAdminError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		// the worker merges the update into the existing configuration
+		merged := make(map[string]interface{})
+		_ = json.Unmarshal(f.docs[entity], &merged)
+		update := make(map[string]interface{})
+		_ = json.Unmarshal(config, &update)
+		for k, v := range update {
+			existing, isMap := merged[k].(map[string]interface{})
+			switch {
+			case v == nil:
+			case isMap && (k == "inputSpecs" || k == "customSchemaInputs" || k == "customSerdeInputs"):
+				// the input topics are merged one by one
+				for topic, spec := range v.(map[string]interface{}) {
+					existing[topic] = spec
+				}
+			default:
+				merged[k] = v
+			}
+		}
+		config, _ = json.Marshal(merged)
+		f.docs[entity] = fakeAdminWorkerDefaults(entity, config)
+		w.WriteHeader(http.StatusNoContent)
+	case sub == "" && r.Method == http.MethodGet:
+		writeFakeAdminRaw(w, f.docs[entity])
+	case sub == "" && r.Method == http.MethodDelete:
+		f.removeLocked(entity)
+		delete(f.stopped, entity)
+		w.WriteHeader(http.StatusNoContent)
+	case sub == "status" && r.Method == http.MethodGet:
+		writeFakeAdminJSON(w, f.instancesStatus(entity))
+	case (sub == "start" || sub == "restart") && r.Method == http.MethodPost:
+		delete(f.stopped, entity)
+		w.WriteHeader(http.StatusNoContent)
+	case sub == "stop" && r.Method == http.MethodPost:
+		f.stopped[entity] = true
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeFakeAdminError(w, http.StatusNotFound, "unknown endpoint")
+	}
+}
+
+func (f *fakeAdminServer) instancesStatus(entity string) map[string]interface{} {
+	var config struct {
+		Parallelism int `json:"parallelism"`
+	}
+	_ = json.Unmarshal(f.docs[entity], &config)
+	if config.Parallelism <= 0 {
+		config.Parallelism = 1
+	}
+
+	instanceErr := f.instanceErrors[entity]
+	running := !f.stopped[entity] && instanceErr == ""
+
+	instances := make([]map[string]interface{}, 0, config.Parallelism)
+	numRunning := 0
+	for i := 0; i < config.Parallelism; i++ {
+		if running {
+			numRunning++
+		}
+		instances = append(instances, map[string]interface{}{
+			"instanceId": i,
+			"status": map[string]interface{}{
+				"running": running,
+				"error":   instanceErr,
+			},
+		})
+	}
+
+	return map[string]interface{}{
+		"numInstances": config.Parallelism,
+		"numRunning":   numRunning,
+		"instances":    instances,
+	}
+}
+
+// collection returns the documents directly below the key by name, e.g. the granted permissions by role
+func (f *fakeAdminServer) collection(key string) map[string]json.RawMessage {
+	result := make(map[string]json.RawMessage)
+	for k, doc := range f.docs {
+		name := strings.TrimPrefix(k, key+"/")
+		if strings.HasPrefix(k, key+"/") && !strings.Contains(name, "/") {
+			result[name] = doc
+		}
+	}
+	return result
+}
+
+func (f *fakeAdminServer) hasChildEntities(entity string) bool {
+	for key := range f.docs {
+		if key == entity {
+			continue
+		}
+		if child, _ := splitFakeAdminPath(key); child == key && fakeAdminParent(key) == entity {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *fakeAdminServer) removeLocked(p string) {
+	for key := range f.docs {
+		if key == p || strings.HasPrefix(key, p+"/") {
+			delete(f.docs, key)
+		}
+	}
+}
+
+// splitFakeAdminPath splits a path into the entity it belongs to and the endpoint below it
+func splitFakeAdminPath(p string) (string, string) {
+	for _, pattern := range fakeAdminEntityPatterns {
+		if entity := pattern.FindString(p); entity != "" {
+			return entity, strings.TrimPrefix(strings.TrimPrefix(p, entity), "/")
+		}
+	}
+	return "", ""
+}
+
+// fakeAdminParent returns the entity that has to exist before the given one is created
+func fakeAdminParent(entity string) string {
+	parts := strings.Split(strings.TrimPrefix(entity, "/admin/"), "/")
+	switch {
+	case parts[1] == "namespaces":
+		return "/admin/v2/tenants/" + parts[2]
+	case parts[1] == "persistent" || parts[1] == "non-persistent" || parts[0] == "v3":
+		return fmt.Sprintf("/admin/v2/namespaces/%s/%s", parts[2], parts[3])
+	}
+	return ""
+}
+
+func fakeAdminDocument(body []byte) json.RawMessage {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return json.RawMessage("{}")
+	}
+	return append(json.RawMessage(nil), body...)
+}
+
+// fakeAdminWorkerDefaults fills the producer config of functions the way the worker does
+func fakeAdminWorkerDefaults(entity string, config json.RawMessage) json.RawMessage {
+	if !strings.HasPrefix(entity, "/admin/v3/functions/") {
+		return config
+	}
+	doc := make(map[string]interface{})
+	if err := json.Unmarshal(config, &doc); err != nil {
+		return config
+	}
+	producerConfig, _ := doc["producerConfig"].(map[string]interface{})
+	if producerConfig == nil {
+		producerConfig = make(map[string]interface{})
+	}
+	if compressionType, _ := producerConfig["compressionType"].(string); compressionType == "" {
+		producerConfig["compressionType"] = "LZ4"
+	}
+	doc["producerConfig"] = producerConfig
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return config
+	}
+	return b
+}
+
+// fakeAdminOffloadDefaults fills the tuning knobs of the offload policies the way the brokers do
+func fakeAdminOffloadDefaults(policies json.RawMessage) json.RawMessage {
+	doc := make(map[string]interface{})
+	if err := json.Unmarshal(policies, &doc); err != nil {
+		return policies
+	}
+	for key, value := range map[string]interface{}{
+		"managedLedgerOffloadMaxThreads":     2,
+		"managedLedgerOffloadPrefetchRounds": 1,
+		"managedLedgerOffloadedReadPriority": "TIERED_STORAGE_FIRST",
+	} {
+		if _, ok := doc[key]; !ok {
+			doc[key] = value
+		}
+	}
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return policies
+	}
+	return b
+}
+
+// fakeAdminInstanceConfig extracts the functionConfig, sinkConfig or sourceConfig part of a multipart form
+func fakeAdminInstanceConfig(r *http.Request, body []byte) (json.RawMessage, error) {
+	_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, err
+	}
+
+	reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil, fmt.Errorf("no config in the form")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasSuffix(part.FormName(), "Config") {
+			config, err := io.ReadAll(part)
+			if err != nil {
+				return nil, err
+			}
+			return config, nil
+		}
+	}
+}
+
+func writeFakeAdminJSON(w http.ResponseWriter, value interface{}) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		writeFakeAdminError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeFakeAdminRaw(w, b)
+}
+
+func writeFakeAdminRaw(w http.ResponseWriter, b []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(b)
+}
+
+func writeFakeAdminError(w http.ResponseWriter, status int, reason string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"reason": reason})
+}
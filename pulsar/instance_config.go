@@ -0,0 +1,215 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsar
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/utils"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// instanceConfigKeys names the attributes of a function, a sink or a source which the worker
+// validates together, an empty key means the resource has no such attribute
+type instanceConfigKeys struct {
+	// exactly one of the archives must be given
+	archives []string
+	// at least one of the inputs must be given
+	inputs []string
+	// the topic names, as strings, lists, sets, maps keyed by topic or input specs blocks
+	topics []string
+	// the options which cannot be enabled with effectively once processing guarantees
+	effectivelyOnceConflicts []string
+	processingGuarantees     string
+	retainOrdering           string
+	retainKeyOrdering        string
+	timeout                  string
+	maxMessageRetries        string
+	deadLetterTopic          string
+}
+
+// customizeDiffInstanceConfig reports at plan time the configurations the worker would reject,
+// values which are not known yet are left to the worker
+func customizeDiffInstanceConfig(keys instanceConfigKeys) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		raw := d.GetRawConfig()
+		if raw.IsNull() || !raw.IsKnown() {
+			return nil
+		}
+
+		if len(keys.archives) > 0 {
+			var given []string
+			for _, key := range keys.archives {
+				if !raw.GetAttr(key).IsNull() {
+					given = append(given, key)
+				}
+			}
+			if len(given) != 1 {
+				return fmt.Errorf("ERROR_INVALID_ARCHIVE: exactly one of %s must be set, got %d",
+					strings.Join(keys.archives, ", "), len(given))
+			}
+		}
+
+		if len(keys.inputs) > 0 && !rawConfigHasAny(raw, keys.inputs...) {
+			return fmt.Errorf("ERROR_MISSING_INPUTS: at least one of %s must be set", strings.Join(keys.inputs, ", "))
+		}
+
+		for _, key := range keys.topics {
+			for _, topic := range rawConfigTopics(raw.GetAttr(key)) {
+				if err := validateFullyQualifiedTopic(topic); err != nil {
+					return fmt.Errorf("ERROR_INVALID_TOPIC_NAME: %s: %w", key, err)
+				}
+			}
+		}
+
+		return validateProcessingGuarantees(d, keys)
+	}
+}
+
+// validateProcessingGuarantees mirrors the checks of the worker on the options which depend on
+// the processing guarantees, the worker defaults to ATLEAST_ONCE
+func validateProcessingGuarantees(d *schema.ResourceDiff, keys instanceConfigKeys) error {
+	guarantees := ProcessingGuaranteesAtLeastOnce
+	if keys.processingGuarantees != "" && d.Get(keys.processingGuarantees).(string) != "" {
+		guarantees = d.Get(keys.processingGuarantees).(string)
+	}
+
+	if keys.retainOrdering != "" && keys.retainKeyOrdering != "" &&
+		d.Get(keys.retainOrdering).(bool) && d.Get(keys.retainKeyOrdering).(bool) {
+		return fmt.Errorf("ERROR_INCOMPATIBLE_OPTIONS: %s and %s cannot both be enabled",
+			keys.retainOrdering, keys.retainKeyOrdering)
+	}
+
+	if keys.timeout != "" && d.Get(keys.timeout).(int) > 0 && guarantees != ProcessingGuaranteesAtLeastOnce {
+		return fmt.Errorf("ERROR_INCOMPATIBLE_OPTIONS: %s is only supported with %s processing guarantees",
+			keys.timeout, ProcessingGuaranteesAtLeastOnce)
+	}
+
+	if guarantees == ProcessingGuaranteesEffectivelyOnce {
+		for _, key := range keys.effectivelyOnceConflicts {
+			enabled := false
+			switch v := d.Get(key).(type) {
+			case bool:
+				enabled = v
+			case int:
+				enabled = v > 0
+			}
+			if enabled {
+				return fmt.Errorf("ERROR_INCOMPATIBLE_OPTIONS: %s is not supported with %s processing guarantees",
+					key, ProcessingGuaranteesEffectivelyOnce)
+			}
+		}
+	}
+
+	if keys.deadLetterTopic != "" && keys.maxMessageRetries != "" &&
+		d.Get(keys.deadLetterTopic).(string) != "" && d.Get(keys.maxMessageRetries).(int) <= 0 {
+		if v := d.GetRawConfig().GetAttr(keys.maxMessageRetries); v.IsKnown() {
+			return fmt.Errorf("ERROR_INCOMPATIBLE_OPTIONS: %s requires %s to be greater than 0",
+				keys.deadLetterTopic, keys.maxMessageRetries)
+		}
+	}
+
+	return nil
+}
+
+// rawConfigHasAny tells if one of the attributes is set to a non empty value, or to a value
+// which is not known yet
+func rawConfigHasAny(raw cty.Value, keys ...string) bool {
+	for _, key := range keys {
+		v := raw.GetAttr(key)
+		switch {
+		case v.IsNull():
+			continue
+		case !v.IsWhollyKnown():
+			return true
+		case v.Type() == cty.String:
+			if v.AsString() != "" {
+				return true
+			}
+		case v.CanIterateElements():
+			if v.LengthInt() > 0 {
+				return true
+			}
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// rawConfigTopics returns the known topic names of an attribute, the regex patterns of the input
+// specs are skipped
+func rawConfigTopics(v cty.Value) []string {
+	if v.IsNull() || !v.IsKnown() {
+		return nil
+	}
+
+	ty := v.Type()
+	switch {
+	case ty == cty.String:
+		return []string{v.AsString()}
+	case ty.IsMapType():
+		var topics []string
+		for it := v.ElementIterator(); it.Next(); {
+			k, _ := it.Element()
+			topics = append(topics, k.AsString())
+		}
+		return topics
+	case ty.IsListType() || ty.IsSetType():
+		var topics []string
+		for it := v.ElementIterator(); it.Next(); {
+			_, elem := it.Element()
+			if !elem.IsKnown() || elem.IsNull() {
+				continue
+			}
+			if elem.Type().IsObjectType() {
+				if elem.Type().HasAttribute("is_regex_pattern") {
+					if regex := elem.GetAttr("is_regex_pattern"); regex.IsKnown() && !regex.IsNull() && regex.True() {
+						continue
+					}
+				}
+				elem = elem.GetAttr("key")
+			}
+			topics = append(topics, rawConfigTopics(elem)...)
+		}
+		return topics
+	}
+
+	return nil
+}
+
+// validateFullyQualifiedTopic requires the topic name to give its tenant and namespace, a short
+// name would silently resolve to the public/default namespace
+func validateFullyQualifiedTopic(topic string) error {
+	if _, err := utils.GetTopicName(topic); err != nil {
+		return fmt.Errorf("%q is not a valid topic name: %w", topic, err)
+	}
+
+	localName := topic
+	if i := strings.Index(topic, "://"); i >= 0 {
+		localName = topic[i+len("://"):]
+	}
+	if len(strings.Split(localName, "/")) < 3 {
+		return fmt.Errorf("%q must be fully qualified, e.g. persistent://public/default/%s", topic, localName)
+	}
+
+	return nil
+}
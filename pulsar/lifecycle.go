@@ -0,0 +1,220 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsar
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/utils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	DesiredStateRunning = "running"
+	DesiredStateStopped = "stopped"
+)
+
+// instancesStatus is the part of the function, sink and source status the provider cares about
+type instancesStatus struct {
+	numInstances int
+	numRunning   int
+	// numStopped counts the instances reported as not running, without an error, a restart or an exception
+	numStopped int
+	// numFailing counts the instances not running which restarted or raised exceptions, the error alone
+	// is also reported while the instance is scheduled
+	numFailing int
+	lastError  string
+}
+
+// addInstance counts an instance of the status reported by the worker
+func (s *instancesStatus) addInstance(running bool, instanceErr string, numRestarts int64,
+	exceptions ...[]utils.ExceptionInformation) {
+	msg := lastInstanceError(instanceErr, exceptions...)
+	switch {
+	case !running && (numRestarts > 0 || lastInstanceError("", exceptions...) != ""):
+		s.numFailing++
+		// the error of a failing instance is the most relevant one
+		s.lastError = msg
+		return
+	case !running && instanceErr == "":
+		s.numStopped++
+	}
+	if msg != "" && s.numFailing == 0 {
+		s.lastError = msg
+	}
+}
+
+// lifecycleClient binds the start/stop/restart/status calls of a single function, sink or source
+type lifecycleClient struct {
+	start   func() error
+	stop    func() error
+	restart func() error
+	status  func() (*instancesStatus, error)
+}
+
+func schemaDesiredState(description string) *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		Default:      DesiredStateRunning,
+		Description:  description,
+		ValidateFunc: validation.StringInSlice([]string{DesiredStateRunning, DesiredStateStopped}, false),
+	}
+}
+
+func schemaWaitForRunning(description string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Default:     false,
+		Description: description,
+	}
+}
+
+// lifecycleTimeouts bounds how long create and update wait for the instances when wait_for_running is set
+func lifecycleTimeouts() *schema.ResourceTimeout {
+	return &schema.ResourceTimeout{
+		Create: schema.DefaultTimeout(5 * time.Minute),
+		Update: schema.DefaultTimeout(5 * time.Minute),
+	}
+}
+
+func schemaRestartTrigger(description string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeMap,
+		Optional:    true,
+		Description: description,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+	}
+}
+
+// applyLifecycle starts, stops or restarts the instances after a create or an update.
+// The instances are always stopped again when the desired state is stopped, since updating
+// the configuration may bring them back.
+func applyLifecycle(d *schema.ResourceData, c lifecycleClient, desiredStateKey, restartTriggerKey string) error {
+	desiredState := d.Get(desiredStateKey).(string)
+
+	if desiredState == DesiredStateStopped {
+		if d.IsNewResource() || d.HasChangeExcept(restartTriggerKey) {
+			if err := c.stop(); err != nil {
+				return fmt.Errorf("ERROR_STOP_INSTANCES: %w", err)
+			}
+		}
+		return nil
+	}
+
+	if d.IsNewResource() {
+		return nil
+	}
+
+	if d.HasChange(desiredStateKey) {
+		if err := c.start(); err != nil {
+			return fmt.Errorf("ERROR_START_INSTANCES: %w", err)
+		}
+	} else if d.HasChange(restartTriggerKey) {
+		if err := c.restart(); err != nil {
+			return fmt.Errorf("ERROR_RESTART_INSTANCES: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// readLifecycleState returns the actual state of the instances, they are considered running as
+// soon as one of them runs and stopped when the worker reports all of them as stopped. The instances
+// still being scheduled or failing keep the prior state.
+func readLifecycleState(c lifecycleClient, prior string) (string, error) {
+	status, err := c.status()
+	if err != nil {
+		return "", fmt.Errorf("ERROR_READ_INSTANCES_STATUS: %w", err)
+	}
+
+	switch {
+	case status.numRunning > 0:
+		return DesiredStateRunning, nil
+	case status.numInstances > 0 && status.numStopped >= status.numInstances:
+		return DesiredStateStopped, nil
+	case prior == "":
+		return DesiredStateRunning, nil
+	}
+	return prior, nil
+}
+
+// waitForRunning polls the instances status until the expected number of instances runs, the
+// number of instances reported by the worker is expected when parallelism is not set
+func waitForRunning(ctx context.Context, c lifecycleClient, parallelism int, timeout time.Duration) error {
+	var lastStatus *instancesStatus
+
+	err := resource.RetryContext(ctx, timeout, func() *resource.RetryError {
+		status, err := c.status()
+		if err != nil {
+			// the status is not available until the instances are scheduled
+			return resource.RetryableError(fmt.Errorf("ERROR_READ_INSTANCES_STATUS: %w", err))
+		}
+		lastStatus = status
+
+		// a crash looping instance would not run before the timeout
+		if status.numFailing > 0 {
+			return resource.NonRetryableError(fmt.Errorf("%d of %d instances failing", status.numFailing,
+				status.numInstances))
+		}
+
+		expected := parallelism
+		if expected <= 0 {
+			expected = status.numInstances
+		}
+		if expected > 0 && status.numRunning >= expected {
+			return nil
+		}
+
+		return resource.RetryableError(fmt.Errorf("%d of %d instances running", status.numRunning, expected))
+	})
+	if err != nil {
+		if lastStatus != nil && lastStatus.lastError != "" {
+			return fmt.Errorf("ERROR_WAIT_FOR_RUNNING: %w, last instance error: %s", err, lastStatus.lastError)
+		}
+		return fmt.Errorf("ERROR_WAIT_FOR_RUNNING: %w", err)
+	}
+
+	return nil
+}
+
+// lastInstanceError returns the error reported by an instance, or its most recent exception
+func lastInstanceError(instanceErr string, exceptions ...[]utils.ExceptionInformation) string {
+	if instanceErr != "" {
+		return instanceErr
+	}
+
+	var latest *utils.ExceptionInformation
+	for _, list := range exceptions {
+		for i := range list {
+			if latest == nil || list[i].TimestampMs > latest.TimestampMs {
+				latest = &list[i]
+			}
+		}
+	}
+	if latest != nil {
+		return latest.ExceptionString
+	}
+
+	return ""
+}
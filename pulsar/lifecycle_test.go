@@ -0,0 +1,100 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsar
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/utils"
+)
+
+func TestReadLifecycleState(t *testing.T) {
+	cases := []struct {
+		name     string
+		status   instancesStatus
+		prior    string
+		expected string
+	}{
+		{"running", instancesStatus{numInstances: 2, numRunning: 1}, DesiredStateStopped, DesiredStateRunning},
+		{"stopped", instancesStatus{numInstances: 2, numStopped: 2}, DesiredStateRunning, DesiredStateStopped},
+		{"scheduling", instancesStatus{numInstances: 2}, DesiredStateRunning, DesiredStateRunning},
+		{"crash looping", instancesStatus{numInstances: 2, numStopped: 1}, DesiredStateStopped, DesiredStateStopped},
+		{"imported while scheduling", instancesStatus{numInstances: 1}, "", DesiredStateRunning},
+	}
+
+	for _, c := range cases {
+		status := c.status
+		client := lifecycleClient{status: func() (*instancesStatus, error) { return &status, nil }}
+
+		state, err := readLifecycleState(client, c.prior)
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if state != c.expected {
+			t.Errorf("%s: expected %s, got %s", c.name, c.expected, state)
+		}
+	}
+}
+
+func TestInstancesStatusAddInstance(t *testing.T) {
+	exceptions := []utils.ExceptionInformation{{ExceptionString: "java.lang.RuntimeException: boom", TimestampMs: 1}}
+
+	cases := []struct {
+		name        string
+		running     bool
+		instanceErr string
+		numRestarts int64
+		exceptions  []utils.ExceptionInformation
+		expected    instancesStatus
+	}{
+		{"running", true, "", 0, nil, instancesStatus{}},
+		{"stopped", false, "", 0, nil, instancesStatus{numStopped: 1}},
+		{"scheduling", false, "Function not scheduled", 0, nil, instancesStatus{lastError: "Function not scheduled"}},
+		{"restarting", false, "", 2, nil, instancesStatus{numFailing: 1}},
+		{"raising", false, "", 0, exceptions, instancesStatus{numFailing: 1, lastError: "java.lang.RuntimeException: boom"}},
+		{"recovered", true, "", 2, exceptions, instancesStatus{lastError: "java.lang.RuntimeException: boom"}},
+	}
+
+	for _, c := range cases {
+		var status instancesStatus
+		status.addInstance(c.running, c.instanceErr, c.numRestarts, c.exceptions)
+		if status != c.expected {
+			t.Errorf("%s: expected %+v, got %+v", c.name, c.expected, status)
+		}
+	}
+}
+
+func TestWaitForRunningFailsFast(t *testing.T) {
+	polls := 0
+	client := lifecycleClient{status: func() (*instancesStatus, error) {
+		polls++
+		return &instancesStatus{numInstances: 1, numFailing: 1, lastError: "java.lang.RuntimeException: boom"}, nil
+	}}
+
+	err := waitForRunning(context.Background(), client, 1, time.Minute)
+	if err == nil || !strings.Contains(err.Error(), "ERROR_WAIT_FOR_RUNNING") ||
+		!strings.Contains(err.Error(), "java.lang.RuntimeException: boom") {
+		t.Fatalf("expected the instance error to be reported, got %v", err)
+	}
+	if polls != 1 {
+		t.Fatalf("expected a failing instance to stop the wait, polled %d times", polls)
+	}
+}
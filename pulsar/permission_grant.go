@@ -20,14 +20,38 @@ package pulsar
 import (
 	"bytes"
 	"fmt"
+	"sort"
 
 	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/utils"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 
 	"github.com/streamnative/terraform-provider-pulsar/hashcode"
 	"github.com/streamnative/terraform-provider-pulsar/types"
 )
 
+const (
+	PermissionGrantModeAdditive      = "additive"
+	PermissionGrantModeAuthoritative = "authoritative"
+)
+
+func schemaPermissionGrantMode() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Default:     PermissionGrantModeAdditive,
+		Description: descriptions["permission_grant_mode"],
+		ValidateFunc: validation.StringInSlice([]string{
+			PermissionGrantModeAdditive,
+			PermissionGrantModeAuthoritative,
+		}, false),
+	}
+}
+
+func isPermissionGrantAuthoritative(d *schema.ResourceData) bool {
+	return d.Get("permission_grant_mode").(string) == PermissionGrantModeAuthoritative
+}
+
 func permissionGrantToHash(v interface{}) int {
 	var buf bytes.Buffer
 	m := v.(map[string]interface{})
@@ -63,9 +87,23 @@ func unmarshalPermissionGrants(v *schema.Set) ([]*types.PermissionGrant, error)
 	return permissionGrants, nil
 }
 
+// setPermissionGrant stores the server grants in the state. In additive mode only the roles managed by
+// this resource are kept, in authoritative mode every role shows up so that unmanaged grants become drift.
 func setPermissionGrant(d *schema.ResourceData, grants map[string][]utils.AuthAction) {
+	storePermissionGrant(d, grants, isPermissionGrantAuthoritative(d))
+}
+
+// storePermissionGrant stores every server grant when allRoles is set, e.g. on import where no role is
+// managed yet, or only the roles managed by this resource
+func storePermissionGrant(d *schema.ResourceData, grants map[string][]utils.AuthAction, allRoles bool) {
+	managedRoles := permissionGrantRoles(d.Get("permission_grant").(*schema.Set))
+
 	permissionGrants := []interface{}{}
 	for role, roleActions := range grants {
+		if !allRoles && !managedRoles[role] {
+			continue
+		}
+
 		actions := []string{}
 		for _, action := range roleActions {
 			actions = append(actions, action.String())
@@ -78,3 +116,57 @@ func setPermissionGrant(d *schema.ResourceData, grants map[string][]utils.AuthAc
 
 	_ = d.Set("permission_grant", schema.NewSet(permissionGrantToHash, permissionGrants))
 }
+
+func permissionGrantRoles(v *schema.Set) map[string]bool {
+	roles := make(map[string]bool)
+	for _, grant := range v.List() {
+		roles[grant.(map[string]interface{})["role"].(string)] = true
+	}
+	return roles
+}
+
+// diffPermissionGrants computes the exact changes to turn the current server grants into the desired ones.
+// Roles whose actions changed are only granted again, the grant replaces their previous actions. Roles not
+// desired anymore are revoked when they were previously managed, or always in
+// authoritative mode. Revokes must be applied before grants.
+func diffPermissionGrants(current map[string][]utils.AuthAction, desired []*types.PermissionGrant,
+	previouslyManaged map[string]bool, authoritative bool) ([]string, []*types.PermissionGrant) {
+	desiredByRole := make(map[string]*types.PermissionGrant, len(desired))
+	for _, grant := range desired {
+		desiredByRole[grant.Role] = grant
+	}
+
+	revokes := make([]string, 0)
+	for role := range current {
+		if _, ok := desiredByRole[role]; !ok && (authoritative || previouslyManaged[role]) {
+			revokes = append(revokes, role)
+		}
+	}
+	sort.Strings(revokes)
+
+	grants := make([]*types.PermissionGrant, 0)
+	for _, grant := range desired {
+		if actions, ok := current[grant.Role]; !ok || !sameAuthActions(actions, grant.Actions) {
+			grants = append(grants, grant)
+		}
+	}
+
+	return revokes, grants
+}
+
+func sameAuthActions(a, b []utils.AuthAction) bool {
+	set := make(map[string]bool, len(a))
+	for _, action := range a {
+		set[action.String()] = true
+	}
+
+	other := make(map[string]bool, len(b))
+	for _, action := range b {
+		if !set[action.String()] {
+			return false
+		}
+		other[action.String()] = true
+	}
+
+	return len(set) == len(other)
+}
@@ -26,6 +26,7 @@ import (
 
 	pulsaradmin "github.com/apache/pulsar-client-go/pulsaradmin/pkg/admin"
 	adminconfig "github.com/apache/pulsar-client-go/pulsaradmin/pkg/admin/config"
+	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/rest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/pkg/errors"
@@ -49,6 +50,9 @@ func init() {
 		"tls_allow_insecure_connection":  "Boolean flag to accept untrusted TLS certificates",
 		"admin_roles":                    "Admin roles to be attached to tenant",
 		"allowed_clusters":               "Tenant will be able to interact with these clusters",
+		"cluster_peers_cluster":          "Name of the cluster whose peer clusters are managed",
+		"cluster_peers_peer_clusters":    "The clusters the cluster peers with, this resource owns the whole list",
+		"tenant_force_destroy":           "Delete the namespaces of the tenant, with their functions, sinks, sources and topics, when the tenant is destroyed",
 		"namespace":                      "Pulsar namespaces are logical groupings of topics",
 		"tenant":                         "An administrative unit for allocating capacity and enforcing an authentication/authorization scheme",
 		"namespace_list":                 "List of namespaces for a given tenant",
@@ -62,6 +66,15 @@ func init() {
 		"subscription_dispatch_rate":     "Data transfer rate for all the subscriptions under the given namespace",
 		"persistence_policy":             "Policy for the namespace for data persistence",
 		"backlog_quota":                  "",
+		"topic_replication_clusters":     "Topic level geo-replication clusters, falls back to the namespace replication clusters when not set",
+		"replicated_subscriptions":       "Names of the existing subscriptions whose state is replicated across the replication clusters, the subscriptions which do not exist yet are skipped",
+		"permission_grant_mode":          "How permission_grant blocks are reconciled: `additive` only manages the declared roles, `authoritative` also revokes every role not declared",
+		"permission_grant_namespace":     "Namespace to grant the permissions on, in the tenant/namespace format",
+		"permission_grant_topic":         "Fully qualified topic to grant the permissions on, e.g. persistent://tenant/namespace/topic",
+		"permission_grant_role":          "Role the permissions are granted to",
+		"permission_grant_actions":       "Actions granted to the role: produce, consume, functions, sinks, sources, packages",
+		"subscription_permission":        "Roles allowed to use a given subscription name on the topics of the namespace. In `additive` permission_grant_mode only the declared subscriptions are read back, and none when the block is not configured; in `authoritative` mode every subscription of the namespace is read back and the ones not declared are revoked",
+		"offload_policies":               "Tiered storage offload policies, credentials are only sent to the broker and never read back",
 		"issuer_url":                     "The OAuth 2.0 URL of the authentication provider which allows the Pulsar client to obtain an access token",
 		"audience":                       "The OAuth 2.0 resource server identifier for the Pulsar cluster",
 		"client_id":                      "The OAuth 2.0 client identifier",
@@ -70,10 +83,12 @@ func init() {
 	}
 }
 
-// PulsarClientBundle is a struct that holds the pulsar admin client for both v2 and v3 api versions
+// PulsarClientBundle is a struct that holds the pulsar admin client for both v2 and v3 api versions,
+// plus a raw rest client for the admin endpoints not covered by the admin client
 type PulsarClientBundle struct {
-	Client   pulsaradmin.Client
-	V3Client pulsaradmin.Client
+	Client     pulsaradmin.Client
+	V3Client   pulsaradmin.Client
+	RestClient *rest.Client
 }
 
 // Provider returns a schema.Provider
@@ -153,13 +168,16 @@ func Provider() *schema.Provider {
 			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"pulsar_cluster":   resourcePulsarCluster(),
-			"pulsar_tenant":    resourcePulsarTenant(),
-			"pulsar_namespace": resourcePulsarNamespace(),
-			"pulsar_topic":     resourcePulsarTopic(),
-			"pulsar_source":    resourcePulsarSource(),
-			"pulsar_sink":      resourcePulsarSink(),
-			"pulsar_function":  resourcePulsarFunction(),
+			"pulsar_cluster":          resourcePulsarCluster(),
+			"pulsar_cluster_peers":    resourcePulsarClusterPeers(),
+			"pulsar_tenant":           resourcePulsarTenant(),
+			"pulsar_namespace":        resourcePulsarNamespace(),
+			"pulsar_topic":            resourcePulsarTopic(),
+			"pulsar_source":           resourcePulsarSource(),
+			"pulsar_sink":             resourcePulsarSink(),
+			"pulsar_function":         resourcePulsarFunction(),
+			"pulsar_permission_grant": resourcePulsarPermissionGrant(),
+			"pulsar_package":          resourcePulsarPackage(),
 		},
 	}
 
@@ -262,9 +280,17 @@ func providerConfigure(d *schema.ResourceData, tfVersion string) (interface{}, d
 		return nil, diag.FromErr(err)
 	}
 
+	restClient, err := admin.NewPulsarRestClient(&admin.PulsarAdminConfig{
+		Config: config,
+	})
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+
 	clientBundle := PulsarClientBundle{
-		Client:   client,
-		V3Client: clientV3,
+		Client:     client,
+		V3Client:   clientV3,
+		RestClient: restClient,
 	}
 
 	return clientBundle, nil
@@ -18,20 +18,22 @@
 package pulsar
 
 import (
-	"bytes"
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/rest"
-	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/utils"
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 
-	"github.com/streamnative/terraform-provider-pulsar/hashcode"
+	"github.com/streamnative/terraform-provider-pulsar/types"
 )
 
 func resourcePulsarCluster() *schema.Resource {
-	return &schema.Resource{
+	r := &schema.Resource{
 		CreateContext: resourcePulsarClusterCreate,
 		ReadContext:   resourcePulsarClusterRead,
 		UpdateContext: resourcePulsarClusterUpdate,
@@ -46,6 +48,9 @@ func resourcePulsarCluster() *schema.Resource {
 				return []*schema.ResourceData{d}, nil
 			},
 		},
+		CustomizeDiff: customizeDiffClusterPeerClusters,
+		// cluster_data used to be a set, which did not diff the values left out of its hash
+		SchemaVersion: 1,
 		Schema: map[string]*schema.Schema{
 			"cluster": {
 				Type:        schema.TypeString,
@@ -53,7 +58,7 @@ func resourcePulsarCluster() *schema.Resource {
 				Description: "Name of the cluster",
 			},
 			"cluster_data": {
-				Type:        schema.TypeSet,
+				Type:        schema.TypeList,
 				Description: "Specific configs of this cluster",
 				Required:    true,
 				MinItems:    1,
@@ -83,29 +88,154 @@ func resourcePulsarCluster() *schema.Resource {
 						"peer_clusters": {
 							Type:     schema.TypeList,
 							Optional: true,
+							Description: "The peer clusters, left as they are when not set, e.g. when managed by " +
+								"pulsar_cluster_peers",
+							DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+								_, configured := rawConfigPeerClusters(d.GetRawConfig())
+								return !configured
+							},
 							Elem: &schema.Schema{
 								Type:         schema.TypeString,
 								ValidateFunc: validateNotBlank,
 							},
 						},
+						"proxy_service_url": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Description:  "The url of the proxy the brokers of the other clusters go through to reach this cluster",
+							ValidateFunc: validateURL,
+						},
+						"proxy_protocol": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Description:  "The protocol of the proxy, only `SNI` is supported",
+							ValidateFunc: validation.StringInSlice([]string{"SNI"}, false),
+						},
+						"authentication_plugin": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The authentication plugin the brokers of the other clusters use to replicate to this cluster",
+						},
+						"authentication_parameters": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Sensitive:   true,
+							Description: "The parameters of the authentication plugin",
+						},
+						"broker_client_tls_enabled": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Whether the brokers of the other clusters use TLS to replicate to this cluster",
+						},
+						"tls_allow_insecure_connection": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Whether the replication accepts untrusted TLS certificates",
+						},
+						"broker_client_trust_certs_file_path": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Path to the trusted TLS certificates of the replication",
+						},
+						"broker_client_certificate_file_path": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Path to the TLS client certificate of the replication",
+						},
+						"broker_client_key_file_path": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Path to the TLS client key of the replication",
+						},
+						"broker_client_tls_enabled_with_keystore": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Whether the replication uses the key stores instead of the PEM files",
+						},
+						"broker_client_tls_trust_store_type": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "JKS",
+							Description:  "The type of the trust store, `JKS` or `PKCS12`",
+							ValidateFunc: validation.StringInSlice([]string{"JKS", "PKCS12"}, false),
+						},
+						"broker_client_tls_trust_store": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Path to the trust store of the replication",
+						},
+						"broker_client_tls_trust_store_password": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Sensitive:   true,
+							Description: "The password of the trust store",
+						},
+						"broker_client_tls_key_store_type": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "JKS",
+							Description:  "The type of the key store, `JKS` or `PKCS12`",
+							ValidateFunc: validation.StringInSlice([]string{"JKS", "PKCS12"}, false),
+						},
+						"broker_client_tls_key_store": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Path to the key store of the replication",
+						},
+						"broker_client_tls_key_store_password": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Sensitive:   true,
+							Description: "The password of the key store",
+						},
+						"listener_name": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The advertised listener the brokers of the other clusters connect to",
+						},
 					},
 				},
-				Set: clusterDataToHash,
 			},
 		},
 	}
+
+	r.StateUpgraders = []schema.StateUpgrader{
+		{
+			Version: 0,
+			Type:    resourcePulsarClusterV0(r.Schema).CoreConfigSchema().ImpliedType(),
+			// the sets and the lists are both stored as arrays
+			Upgrade: func(ctx context.Context, rawState map[string]interface{},
+				meta interface{}) (map[string]interface{}, error) {
+				return rawState, nil
+			},
+		},
+	}
+
+	return r
+}
+
+// resourcePulsarClusterV0 is the schema before cluster_data became a list, only its types matter
+func resourcePulsarClusterV0(current map[string]*schema.Schema) *schema.Resource {
+	v0 := make(map[string]*schema.Schema, len(current))
+	for k, v := range current {
+		v0[k] = v
+	}
+	clusterData := *v0["cluster_data"]
+	clusterData.Type = schema.TypeSet
+	v0["cluster_data"] = &clusterData
+	return &schema.Resource{Schema: v0}
 }
 
 func resourcePulsarClusterCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := getClientFromMeta(meta).Clusters()
+	client := getRestClientFromMeta(meta)
 
 	cluster := d.Get("cluster").(string)
-	clusterDataSet := d.Get("cluster_data").(*schema.Set)
-
-	clusterData := unmarshalClusterData(clusterDataSet)
-	clusterData.Name = cluster
+	clusterData := unmarshalClusterData(d.Get("cluster_data").([]interface{}))
 
-	if err := client.Create(*clusterData); err != nil {
+	if err := client.Put(clusterRestEndpoint(cluster), clusterData); err != nil {
 		return diag.FromErr(fmt.Errorf("ERROR_CREATE_CLUSTER: %w", err))
 	}
 
@@ -115,11 +245,12 @@ func resourcePulsarClusterCreate(ctx context.Context, d *schema.ResourceData, me
 }
 
 func resourcePulsarClusterRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := getClientFromMeta(meta).Clusters()
+	client := getRestClientFromMeta(meta)
 
 	cluster := d.Get("cluster").(string)
 
-	clusterData, err := client.Get(cluster)
+	var clusterData types.ClusterData
+	err := client.Get(clusterRestEndpoint(cluster), &clusterData)
 	if err != nil {
 		if cliErr, ok := err.(rest.Error); ok && cliErr.Code == 404 {
 			return diag.Errorf("ERROR_CLUSTER_NOT_FOUND")
@@ -127,41 +258,128 @@ func resourcePulsarClusterRead(ctx context.Context, d *schema.ResourceData, meta
 		return diag.FromErr(fmt.Errorf("ERROR_READ_CLUSTER_DATA: %w", err))
 	}
 
-	peerClusterNames := make([]interface{}, len(clusterData.PeerClusterNames))
-	for i, cl := range clusterData.PeerClusterNames {
-		peerClusterNames[i] = cl
-	}
-
 	d.SetId(cluster)
-	_ = d.Set("cluster_data", schema.NewSet(clusterDataToHash, []interface{}{
-		map[string]interface{}{
-			"web_service_url":        clusterData.ServiceURL,
-			"web_service_url_tls":    clusterData.ServiceURLTls,
-			"broker_service_url":     clusterData.BrokerServiceURL,
-			"broker_service_url_tls": clusterData.BrokerServiceURLTls,
-			"peer_clusters":          peerClusterNames,
-		},
-	}))
+	_ = d.Set("cluster_data", []interface{}{
+		flattenClusterData(&clusterData, d.Get("cluster_data").([]interface{})),
+	})
 
 	return nil
 }
 
 func resourcePulsarClusterUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := getClientFromMeta(meta).Clusters()
+	client := getRestClientFromMeta(meta)
 
-	clusterDataSet := d.Get("cluster_data").(*schema.Set)
 	cluster := d.Get("cluster").(string)
 
-	clusterData := unmarshalClusterData(clusterDataSet)
-	clusterData.Name = cluster
+	clusterData := unmarshalClusterData(d.Get("cluster_data").([]interface{}))
+
+	if _, configured := rawConfigPeerClusters(d.GetRawConfig()); !configured {
+		// keep the peers set since the last refresh, e.g. by pulsar_cluster_peers
+		peers, err := getClientFromMeta(meta).Clusters().GetPeerClusters(cluster)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("ERROR_READ_CLUSTER_DATA: GetPeerClusters: %w", err))
+		}
+		clusterData.PeerClusterNames = peers
+	}
 
-	if err := client.Update(*clusterData); err != nil {
+	if err := client.Post(clusterRestEndpoint(cluster), clusterData); err != nil {
 		return diag.FromErr(fmt.Errorf("ERROR_UPDATE_CLUSTER_DATA: %w", err))
 	}
 
-	_ = d.Set("cluster_data", clusterDataSet)
 	d.SetId(cluster)
 
+	return resourcePulsarClusterRead(ctx, d, meta)
+}
+
+// customizeDiffClusterPeerClusters checks the configured peer clusters, the peers left to the
+// server are not checked
+func customizeDiffClusterPeerClusters(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.HasChange("cluster_data") {
+		return nil
+	}
+
+	peers, configured := rawConfigPeerClusters(d.GetRawConfig())
+	if !configured {
+		return nil
+	}
+
+	return validatePeerClusters(d.Get("cluster").(string), peers, meta)
+}
+
+// rawConfigPeerClusters returns the known peer clusters of the cluster data configuration, and
+// whether peer_clusters is set at all
+func rawConfigPeerClusters(raw cty.Value) ([]string, bool) {
+	if raw.IsNull() || !raw.IsKnown() {
+		return nil, false
+	}
+
+	clusterData := raw.GetAttr("cluster_data")
+	if clusterData.IsNull() || !clusterData.IsKnown() {
+		return nil, false
+	}
+
+	var peers []string
+	configured := false
+	for it := clusterData.ElementIterator(); it.Next(); {
+		_, elem := it.Element()
+		if !elem.IsKnown() || elem.IsNull() {
+			continue
+		}
+		peerClusters := elem.GetAttr("peer_clusters")
+		if peerClusters.IsNull() {
+			continue
+		}
+		configured = true
+		if !peerClusters.IsKnown() {
+			continue
+		}
+		for pit := peerClusters.ElementIterator(); pit.Next(); {
+			_, peer := pit.Element()
+			if peer.IsKnown() && !peer.IsNull() {
+				peers = append(peers, peer.AsString())
+			}
+		}
+	}
+
+	return peers, configured
+}
+
+// validatePeerClusters rejects the peer clusters the brokers would, the cluster itself and the
+// clusters which do not exist. The peers which are not known yet, e.g. the id of a cluster created
+// in the same apply, are left out by the callers.
+func validatePeerClusters(cluster string, peers []string, meta interface{}) error {
+	if len(peers) == 0 {
+		return nil
+	}
+
+	for _, peer := range peers {
+		if peer == cluster {
+			return fmt.Errorf("ERROR_INVALID_PEER_CLUSTERS: cluster %s cannot be its own peer", cluster)
+		}
+	}
+
+	clusters, err := getClientFromMeta(meta).Clusters().List()
+	if err != nil {
+		return fmt.Errorf("ERROR_READ_CLUSTERS: %w", err)
+	}
+
+	exists := make(map[string]bool, len(clusters))
+	for _, c := range clusters {
+		exists[c] = true
+	}
+
+	var missing []string
+	for _, peer := range peers {
+		if !exists[peer] {
+			missing = append(missing, peer)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("ERROR_PEER_CLUSTER_NOT_FOUND: peer clusters %s do not exist, reference the id of the "+
+			"pulsar_cluster of a peer created in the same apply", strings.Join(missing, ", "))
+	}
+
 	return nil
 }
 
@@ -180,35 +398,99 @@ func resourcePulsarClusterDelete(ctx context.Context, d *schema.ResourceData, me
 	return nil
 }
 
-func clusterDataToHash(v interface{}) int {
-	var buf bytes.Buffer
-	m := v.(map[string]interface{})
-
-	buf.WriteString(fmt.Sprintf("%s-", m["web_service_url"].(string)))
-	buf.WriteString(fmt.Sprintf("%s-", m["web_service_url_tls"].(string)))
-	buf.WriteString(fmt.Sprintf("%s-", m["broker_service_url"].(string)))
-	buf.WriteString(fmt.Sprintf("%s-", m["broker_service_url_tls"].(string)))
-	peerClusters := m["peer_clusters"].([]interface{})
-
-	for _, pc := range peerClusters {
-		buf.WriteString(fmt.Sprintf("%s-", pc.(string)))
-	}
-
-	return hashcode.String(buf.String())
+// clusterDataSensitiveKeys are kept from the state when the brokers do not return them
+var clusterDataSensitiveKeys = []string{
+	"authentication_parameters",
+	"broker_client_tls_trust_store_password",
+	"broker_client_tls_key_store_password",
 }
 
-func unmarshalClusterData(input *schema.Set) *utils.ClusterData {
-	var cd utils.ClusterData
+func unmarshalClusterData(input []interface{}) *types.ClusterData {
+	var cd types.ClusterData
 
-	for _, v := range input.List() {
-		data := v.(map[string]interface{})
+	for _, v := range input {
+		data, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
 
 		cd.ServiceURL = data["web_service_url"].(string)
 		cd.ServiceURLTls = data["web_service_url_tls"].(string)
 		cd.BrokerServiceURL = data["broker_service_url"].(string)
 		cd.BrokerServiceURLTls = data["broker_service_url_tls"].(string)
 		cd.PeerClusterNames = handleHCLArrayV2(data["peer_clusters"].([]interface{}))
+		cd.ProxyServiceURL = data["proxy_service_url"].(string)
+		cd.ProxyProtocol = data["proxy_protocol"].(string)
+		cd.AuthenticationPlugin = data["authentication_plugin"].(string)
+		cd.AuthenticationParameters = data["authentication_parameters"].(string)
+		cd.BrokerClientTLSEnabled = data["broker_client_tls_enabled"].(bool)
+		cd.TLSAllowInsecureConnection = data["tls_allow_insecure_connection"].(bool)
+		cd.BrokerClientTrustCertsFilePath = data["broker_client_trust_certs_file_path"].(string)
+		cd.BrokerClientCertificateFilePath = data["broker_client_certificate_file_path"].(string)
+		cd.BrokerClientKeyFilePath = data["broker_client_key_file_path"].(string)
+		cd.BrokerClientTLSEnabledWithKeyStore = data["broker_client_tls_enabled_with_keystore"].(bool)
+		cd.BrokerClientTLSTrustStoreType = data["broker_client_tls_trust_store_type"].(string)
+		cd.BrokerClientTLSTrustStore = data["broker_client_tls_trust_store"].(string)
+		cd.BrokerClientTLSTrustStorePassword = data["broker_client_tls_trust_store_password"].(string)
+		cd.BrokerClientTLSKeyStoreType = data["broker_client_tls_key_store_type"].(string)
+		cd.BrokerClientTLSKeyStore = data["broker_client_tls_key_store"].(string)
+		cd.BrokerClientTLSKeyStorePassword = data["broker_client_tls_key_store_password"].(string)
+		cd.ListenerName = data["listener_name"].(string)
 	}
 
 	return &cd
 }
+
+// flattenClusterData converts the cluster data read from the brokers, the sensitive values they do
+// not return are kept from the current state
+func flattenClusterData(cd *types.ClusterData, current []interface{}) map[string]interface{} {
+	peerClusterNames := make([]interface{}, len(cd.PeerClusterNames))
+	for i, cl := range cd.PeerClusterNames {
+		peerClusterNames[i] = cl
+	}
+
+	trustStoreType, keyStoreType := cd.BrokerClientTLSTrustStoreType, cd.BrokerClientTLSKeyStoreType
+	if trustStoreType == "" {
+		trustStoreType = "JKS"
+	}
+	if keyStoreType == "" {
+		keyStoreType = "JKS"
+	}
+
+	data := map[string]interface{}{
+		"web_service_url":                         cd.ServiceURL,
+		"web_service_url_tls":                     cd.ServiceURLTls,
+		"broker_service_url":                      cd.BrokerServiceURL,
+		"broker_service_url_tls":                  cd.BrokerServiceURLTls,
+		"peer_clusters":                           peerClusterNames,
+		"proxy_service_url":                       cd.ProxyServiceURL,
+		"proxy_protocol":                          cd.ProxyProtocol,
+		"authentication_plugin":                   cd.AuthenticationPlugin,
+		"authentication_parameters":               cd.AuthenticationParameters,
+		"broker_client_tls_enabled":               cd.BrokerClientTLSEnabled,
+		"tls_allow_insecure_connection":           cd.TLSAllowInsecureConnection,
+		"broker_client_trust_certs_file_path":     cd.BrokerClientTrustCertsFilePath,
+		"broker_client_certificate_file_path":     cd.BrokerClientCertificateFilePath,
+		"broker_client_key_file_path":             cd.BrokerClientKeyFilePath,
+		"broker_client_tls_enabled_with_keystore": cd.BrokerClientTLSEnabledWithKeyStore,
+		"broker_client_tls_trust_store_type":      trustStoreType,
+		"broker_client_tls_trust_store":           cd.BrokerClientTLSTrustStore,
+		"broker_client_tls_trust_store_password":  cd.BrokerClientTLSTrustStorePassword,
+		"broker_client_tls_key_store_type":        keyStoreType,
+		"broker_client_tls_key_store":             cd.BrokerClientTLSKeyStore,
+		"broker_client_tls_key_store_password":    cd.BrokerClientTLSKeyStorePassword,
+		"listener_name":                           cd.ListenerName,
+	}
+
+	if len(current) > 0 {
+		if prev, ok := current[0].(map[string]interface{}); ok {
+			for _, key := range clusterDataSensitiveKeys {
+				if data[key] == "" && prev[key] != nil {
+					data[key] = prev[key]
+				}
+			}
+		}
+	}
+
+	return data
+}
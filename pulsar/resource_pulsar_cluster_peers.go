@@ -0,0 +1,164 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsar
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/rest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourcePulsarClusterPeers manages the peer clusters of a cluster on their own, so that the
+// clusters peering with each other do not depend on each other. The peer_clusters of the
+// pulsar_cluster must then be left unset.
+func resourcePulsarClusterPeers() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourcePulsarClusterPeersCreate,
+		ReadContext:   resourcePulsarClusterPeersRead,
+		UpdateContext: resourcePulsarClusterPeersUpdate,
+		DeleteContext: resourcePulsarClusterPeersDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+				cluster := d.Id()
+				_ = d.Set("cluster", cluster)
+				diags := resourcePulsarClusterPeersRead(ctx, d, meta)
+				if diags.HasError() {
+					return nil, fmt.Errorf("import %q: %s", cluster, diags[0].Summary)
+				}
+				if d.Id() == "" {
+					return nil, fmt.Errorf("import %q: ERROR_CLUSTER_NOT_FOUND", cluster)
+				}
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+		CustomizeDiff: customizeDiffClusterPeers,
+		Schema: map[string]*schema.Schema{
+			"cluster": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  descriptions["cluster_peers_cluster"],
+				ValidateFunc: validateNotBlank,
+			},
+			"peer_clusters": {
+				Type:        schema.TypeSet,
+				Required:    true,
+				Description: descriptions["cluster_peers_peer_clusters"],
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateNotBlank,
+				},
+			},
+		},
+	}
+}
+
+func resourcePulsarClusterPeersCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if err := updatePeerClusters(d, meta); err != nil {
+		return diag.FromErr(fmt.Errorf("ERROR_CREATE_CLUSTER_PEERS: %w", err))
+	}
+
+	d.SetId(d.Get("cluster").(string))
+
+	return resourcePulsarClusterPeersRead(ctx, d, meta)
+}
+
+func resourcePulsarClusterPeersRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	cluster := d.Get("cluster").(string)
+
+	peers, err := getClientFromMeta(meta).Clusters().GetPeerClusters(cluster)
+	if err != nil {
+		if cliErr, ok := err.(rest.Error); ok && cliErr.Code == 404 {
+			// the cluster was deleted outside of terraform
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(fmt.Errorf("ERROR_READ_CLUSTER_PEERS: %w", err))
+	}
+
+	d.SetId(cluster)
+	_ = d.Set("peer_clusters", peers)
+
+	return nil
+}
+
+func resourcePulsarClusterPeersUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if err := updatePeerClusters(d, meta); err != nil {
+		return diag.FromErr(fmt.Errorf("ERROR_UPDATE_CLUSTER_PEERS: %w", err))
+	}
+
+	return resourcePulsarClusterPeersRead(ctx, d, meta)
+}
+
+func resourcePulsarClusterPeersDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	cluster := d.Get("cluster").(string)
+
+	if err := getClientFromMeta(meta).Clusters().UpdatePeerClusters(cluster, []string{}); err != nil {
+		// the cluster is already gone together with its peers
+		if cliErr, ok := err.(rest.Error); ok && cliErr.Code == 404 {
+			return nil
+		}
+		return diag.FromErr(fmt.Errorf("ERROR_DELETE_CLUSTER_PEERS: %w", err))
+	}
+
+	return nil
+}
+
+func updatePeerClusters(d *schema.ResourceData, meta interface{}) error {
+	cluster := d.Get("cluster").(string)
+	peers := handleHCLArrayV2(d.Get("peer_clusters").(*schema.Set).List())
+
+	if err := getClientFromMeta(meta).Clusters().UpdatePeerClusters(cluster, peers); err != nil {
+		return fmt.Errorf("UpdatePeerClusters: %w", err)
+	}
+
+	return nil
+}
+
+// customizeDiffClusterPeers checks the known peer clusters, the peers and the cluster created in
+// the same apply are not known yet
+func customizeDiffClusterPeers(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.HasChanges("cluster", "peer_clusters") {
+		return nil
+	}
+
+	raw := d.GetRawConfig()
+	if raw.IsNull() || !raw.IsKnown() {
+		return nil
+	}
+
+	cluster := ""
+	if v := raw.GetAttr("cluster"); v.IsKnown() && !v.IsNull() {
+		cluster = v.AsString()
+	}
+
+	var peers []string
+	if v := raw.GetAttr("peer_clusters"); v.IsKnown() && !v.IsNull() {
+		for it := v.ElementIterator(); it.Next(); {
+			_, peer := it.Element()
+			if peer.IsKnown() && !peer.IsNull() {
+				peers = append(peers, peer.AsString())
+			}
+		}
+	}
+
+	return validatePeerClusters(cluster, peers, meta)
+}
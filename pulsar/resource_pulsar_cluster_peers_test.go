@@ -0,0 +1,174 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsar
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/utils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func init() {
+	initTestWebServiceURL()
+}
+
+func TestClusterPeers(t *testing.T) {
+	east := testAccRandomName()
+	west := testAccRandomName()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testPulsarClusterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testPulsarClusterPeers(testWebServiceURL, east, west),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("pulsar_cluster_peers.east", "peer_clusters.#", "1"),
+					testPulsarClusterPeersExist(east, west),
+					testPulsarClusterPeersExist(west, east),
+				),
+			},
+			{
+				ResourceName:      "pulsar_cluster_peers.east",
+				ImportState:       true,
+				ImportStateId:     east,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testPulsarClusterPeersExist(cluster, peer string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := getClientFromMeta(testAccProvider.Meta()).Clusters()
+
+		peers, err := client.GetPeerClusters(cluster)
+		if err != nil {
+			return fmt.Errorf("ERROR_READ_CLUSTER_PEERS: %w", err)
+		}
+		for _, p := range peers {
+			if p == peer {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("expected %s to peer with %s, got %v", cluster, peer, peers)
+	}
+}
+
+func testPulsarClusterPeers(url, east, west string) string {
+	return fmt.Sprintf(`
+provider "pulsar" {
+  web_service_url = "%s"
+}
+
+resource "pulsar_cluster" "east" {
+  cluster = "%s"
+
+  cluster_data {
+    web_service_url    = "http://localhost:8080"
+    broker_service_url = "http://localhost:6050"
+  }
+}
+
+resource "pulsar_cluster" "west" {
+  cluster = "%s"
+
+  cluster_data {
+    web_service_url    = "http://localhost:8080"
+    broker_service_url = "http://localhost:6050"
+  }
+}
+
+resource "pulsar_cluster_peers" "east" {
+  cluster       = pulsar_cluster.east.id
+  peer_clusters = [pulsar_cluster.west.id]
+}
+
+resource "pulsar_cluster_peers" "west" {
+  cluster       = pulsar_cluster.west.id
+  peer_clusters = [pulsar_cluster.east.id]
+}
+`, url, east, west)
+}
+
+func TestClusterPeersUnit(t *testing.T) {
+	fake := newFakeAdminServer(t)
+	resourceName := "pulsar_cluster_peers.east"
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testUnitPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: fake.checkDestroyed("pulsar_cluster", func(id string) string {
+			return "/admin/v2/clusters/" + id
+		}),
+		Steps: []resource.TestStep{
+			{
+				Config: testPulsarClusterPeers(fake.URL, "east", "west"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "id", "east"),
+					resource.TestCheckResourceAttr(resourceName, "peer_clusters.#", "1"),
+					func(s *terraform.State) error {
+						var cluster utils.ClusterData
+						fake.get("/admin/v2/clusters/west", &cluster)
+						if len(cluster.PeerClusterNames) != 1 || cluster.PeerClusterNames[0] != "east" {
+							return fmt.Errorf("expected west to peer with east, got %v", cluster.PeerClusterNames)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				// the pulsar_cluster do not fight over the peers they leave unset
+				Config:   testPulsarClusterPeers(fake.URL, "east", "west"),
+				PlanOnly: true,
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateId:     "east",
+				ImportStateVerify: true,
+			},
+			{
+				PreConfig: func() {
+					var cluster utils.ClusterData
+					fake.get("/admin/v2/clusters/east", &cluster)
+					cluster.PeerClusterNames = []string{"west", "standalone"}
+					fake.put("/admin/v2/clusters/east", cluster)
+				},
+				Config:             testPulsarClusterPeers(fake.URL, "east", "west"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testFakeProvider(fake.URL) + `
+resource "pulsar_cluster_peers" "standalone" {
+  cluster       = "standalone"
+  peer_clusters = ["skrulls"]
+}
+`,
+				ExpectError: regexp.MustCompile("ERROR_PEER_CLUSTER_NOT_FOUND"),
+			},
+		},
+	})
+}
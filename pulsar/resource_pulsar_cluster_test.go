@@ -25,13 +25,47 @@ import (
 
 	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/admin"
 	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/utils"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	"github.com/streamnative/terraform-provider-pulsar/types"
 )
 
 func init() {
 	initTestWebServiceURL()
+
+	resource.AddTestSweepers("pulsar_cluster", &resource.Sweeper{
+		Name:         "pulsar_cluster",
+		F:            testSweepClusters,
+		Dependencies: []string{"pulsar_tenant"},
+	})
+}
+
+func testSweepClusters(url string) error {
+	client, err := sharedClient(url)
+	if err != nil {
+		return fmt.Errorf("ERROR_GETTING_PULSAR_CLIENT: %w", err)
+	}
+
+	conn := client.(admin.Client)
+
+	clusters, err := conn.Clusters().List()
+	if err != nil {
+		return fmt.Errorf("ERROR_GETTING_CLUSTERS: %w", err)
+	}
+
+	var errs error
+	for _, cluster := range clusters {
+		if !isSweepable(cluster) {
+			continue
+		}
+		if err := conn.Clusters().Delete(cluster); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("ERROR_DELETING_CLUSTER %s: %w", cluster, err))
+		}
+	}
+
+	return errs
 }
 
 func TestCluster(t *testing.T) {
@@ -50,7 +84,7 @@ func TestCluster(t *testing.T) {
 }
 
 func TestHandleExistingCluster(t *testing.T) {
-	cName := acctest.RandString(10)
+	cName := testAccRandomName()
 
 	resource.Test(t, resource.TestCase{
 		PreCheck: func() {
@@ -69,7 +103,7 @@ func TestHandleExistingCluster(t *testing.T) {
 }
 
 func TestImportExistingCluster(t *testing.T) {
-	cName := acctest.RandString(10)
+	cName := testAccRandomName()
 
 	resource.Test(t, resource.TestCase{
 		PreCheck: func() {
@@ -117,8 +151,8 @@ func testClusterImported() resource.ImportStateCheckFunc {
 			return fmt.Errorf("expected %d states, got %d: %#v", 1, len(s), s)
 		}
 
-		if len(s[0].Attributes) != 11 {
-			return fmt.Errorf("expected %d attrs, got %d: %#v", 11, len(s[0].Attributes), s[0].Attributes)
+		if len(s[0].Attributes) != 28 {
+			return fmt.Errorf("expected %d attrs, got %d: %#v", 28, len(s[0].Attributes), s[0].Attributes)
 		}
 
 		return nil
@@ -179,11 +213,71 @@ resource "pulsar_cluster" "test" {
     web_service_url_tls    = "http://localhost:8443"
     broker_service_url = "http://localhost:6050"
     broker_service_url_tls = "pulsar+ssl://localhost:6051"
-    peer_clusters      = ["skrulls", "krees"]
+    peer_clusters      = ["standalone"]
   }
 }`, testWebServiceURL)
 )
 
+func TestClusterReplicationTLS(t *testing.T) {
+	cName := testAccRandomName()
+	resourceName := "pulsar_cluster.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testPulsarClusterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testPulsarClusterReplicationTLS(testWebServiceURL, cName, "internal"),
+				Check: resource.ComposeTestCheckFunc(
+					testPulsarClusterExists(resourceName),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "cluster_data.*", map[string]string{
+						"proxy_service_url":                  "pulsar+ssl://proxy:6651",
+						"proxy_protocol":                     "SNI",
+						"broker_client_tls_enabled":          "true",
+						"broker_client_tls_trust_store_type": "PKCS12",
+						"listener_name":                      "internal",
+					}),
+				),
+			},
+			{
+				Config: testPulsarClusterReplicationTLS(testWebServiceURL, cName, "external"),
+				Check: resource.TestCheckTypeSetElemNestedAttrs(resourceName, "cluster_data.*", map[string]string{
+					"listener_name": "external",
+				}),
+			},
+		},
+	})
+}
+
+func testPulsarClusterReplicationTLS(url, cname, listenerName string) string {
+	return fmt.Sprintf(`
+provider "pulsar" {
+  web_service_url = "%s"
+}
+
+resource "pulsar_cluster" "test" {
+  cluster = "%s"
+
+  cluster_data {
+    web_service_url                         = "http://localhost:8080"
+    broker_service_url                      = "http://localhost:6050"
+    broker_service_url_tls                  = "pulsar+ssl://localhost:6651"
+    peer_clusters                           = ["standalone"]
+    proxy_service_url                       = "pulsar+ssl://proxy:6651"
+    proxy_protocol                          = "SNI"
+    authentication_plugin                   = "org.apache.pulsar.client.impl.auth.AuthenticationToken"
+    authentication_parameters               = "token:replication"
+    broker_client_tls_enabled               = true
+    broker_client_tls_enabled_with_keystore = true
+    broker_client_tls_trust_store_type      = "PKCS12"
+    broker_client_tls_trust_store           = "/pulsar/conf/truststore.p12"
+    broker_client_tls_trust_store_password  = "changeit"
+    listener_name                           = "%s"
+  }
+}`, url, cname, listenerName)
+}
+
 func testPulsarExistingCluster(url, cname string) string {
 	return fmt.Sprintf(`
 provider "pulsar" {
@@ -200,3 +294,218 @@ resource "pulsar_cluster" "test" {
   }
 }`, url, cname)
 }
+
+func TestClusterUnit(t *testing.T) {
+	fake := newFakeAdminServer(t)
+	resourceName := "pulsar_cluster.test"
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testUnitPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: fake.checkDestroyed("pulsar_cluster", func(id string) string {
+			return "/admin/v2/clusters/" + id
+		}),
+		Steps: []resource.TestStep{
+			{
+				Config: testPulsarClusterUnit(fake.URL, "http://localhost:8080"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "cluster_data.*", map[string]string{
+						"web_service_url": "http://localhost:8080",
+					}),
+				),
+			},
+			{
+				Config: testPulsarClusterUnit(fake.URL, "http://localhost:9090"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "cluster_data.*", map[string]string{
+						"web_service_url": "http://localhost:9090",
+					}),
+					func(s *terraform.State) error {
+						var cluster utils.ClusterData
+						fake.get("/admin/v2/clusters/eternals", &cluster)
+						if cluster.ServiceURL != "http://localhost:9090" {
+							return fmt.Errorf("expected the service url to be updated, got %s", cluster.ServiceURL)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateId:     "eternals",
+				ImportStateVerify: true,
+			},
+			{
+				PreConfig: func() {
+					fake.put("/admin/v2/clusters/eternals", utils.ClusterData{
+						ServiceURL:       "http://elsewhere:8080",
+						BrokerServiceURL: "http://localhost:6050",
+						PeerClusterNames: []string{"standalone"},
+					})
+				},
+				Config:             testPulsarClusterUnit(fake.URL, "http://localhost:9090"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestClusterReplicationTLSUnit(t *testing.T) {
+	fake := newFakeAdminServer(t)
+	resourceName := "pulsar_cluster.test"
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testUnitPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: fake.checkDestroyed("pulsar_cluster", func(id string) string {
+			return "/admin/v2/clusters/" + id
+		}),
+		Steps: []resource.TestStep{
+			{
+				Config: testPulsarClusterReplicationTLS(fake.URL, "eternals", "internal"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "cluster_data.*", map[string]string{
+						"authentication_parameters":        "token:replication",
+						"broker_client_tls_key_store_type": "JKS",
+						"listener_name":                    "internal",
+					}),
+					func(s *terraform.State) error {
+						var cluster types.ClusterData
+						fake.get("/admin/v2/clusters/eternals", &cluster)
+						if cluster.ProxyServiceURL != "pulsar+ssl://proxy:6651" || cluster.ProxyProtocol != "SNI" ||
+							!cluster.BrokerClientTLSEnabledWithKeyStore ||
+							cluster.BrokerClientTLSTrustStorePassword != "changeit" ||
+							cluster.ListenerName != "internal" {
+							return fmt.Errorf("unexpected cluster data %+v", cluster)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateId:     "eternals",
+				ImportStateVerify: true,
+			},
+			{
+				Config: testPulsarClusterReplicationTLS(fake.URL, "eternals", "external"),
+				Check: func(s *terraform.State) error {
+					var cluster types.ClusterData
+					fake.get("/admin/v2/clusters/eternals", &cluster)
+					if cluster.ListenerName != "external" {
+						return fmt.Errorf("expected the listener name to be updated, got %s", cluster.ListenerName)
+					}
+					return nil
+				},
+			},
+			{
+				PreConfig: func() {
+					var cluster types.ClusterData
+					fake.get("/admin/v2/clusters/eternals", &cluster)
+					// the brokers may not return the secrets, they are kept from the state then
+					cluster.AuthenticationParameters = ""
+					cluster.BrokerClientTLSTrustStorePassword = ""
+					fake.put("/admin/v2/clusters/eternals", cluster)
+				},
+				Config:             testPulsarClusterReplicationTLS(fake.URL, "eternals", "external"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: false,
+			},
+			{
+				PreConfig: func() {
+					var cluster types.ClusterData
+					fake.get("/admin/v2/clusters/eternals", &cluster)
+					cluster.ProxyServiceURL = "pulsar+ssl://elsewhere:6651"
+					fake.put("/admin/v2/clusters/eternals", cluster)
+				},
+				Config:             testPulsarClusterReplicationTLS(fake.URL, "eternals", "external"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testPulsarClusterUnit(url, webServiceURL string) string {
+	return testFakeProvider(url) + fmt.Sprintf(`
+resource "pulsar_cluster" "test" {
+  cluster = "eternals"
+
+  cluster_data {
+    web_service_url    = "%s"
+    broker_service_url = "http://localhost:6050"
+    peer_clusters      = ["standalone"]
+  }
+}
+`, webServiceURL)
+}
+
+func TestClusterPeerClustersUnit(t *testing.T) {
+	fake := newFakeAdminServer(t)
+	resourceName := "pulsar_cluster.test"
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testUnitPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: fake.checkDestroyed("pulsar_cluster", func(id string) string {
+			return "/admin/v2/clusters/" + id
+		}),
+		Steps: []resource.TestStep{
+			{
+				Config:      testPulsarClusterPeerClustersUnit(fake.URL, "http://localhost:8080", `["eternals"]`),
+				ExpectError: regexp.MustCompile("ERROR_INVALID_PEER_CLUSTERS"),
+			},
+			{
+				Config:      testPulsarClusterPeerClustersUnit(fake.URL, "http://localhost:8080", `["skrulls"]`),
+				ExpectError: regexp.MustCompile("ERROR_PEER_CLUSTER_NOT_FOUND"),
+			},
+			{
+				Config: testPulsarClusterPeerClustersUnit(fake.URL, "http://localhost:8080", `["standalone"]`),
+				Check:  resource.TestCheckResourceAttr(resourceName, "cluster_data.0.peer_clusters.0", "standalone"),
+			},
+			{
+				// the peers are left to the brokers when they are not configured
+				Config: testPulsarClusterPeerClustersUnit(fake.URL, "http://localhost:9090", ""),
+				Check: func(s *terraform.State) error {
+					var cluster utils.ClusterData
+					fake.get("/admin/v2/clusters/eternals", &cluster)
+					if cluster.ServiceURL != "http://localhost:9090" || len(cluster.PeerClusterNames) != 1 {
+						return fmt.Errorf("expected the peer clusters to be kept, got %+v", cluster)
+					}
+					return nil
+				},
+			},
+			{
+				Config: testPulsarClusterPeerClustersUnit(fake.URL, "http://localhost:9090", "[]"),
+				Check: func(s *terraform.State) error {
+					var cluster utils.ClusterData
+					fake.get("/admin/v2/clusters/eternals", &cluster)
+					if len(cluster.PeerClusterNames) != 0 {
+						return fmt.Errorf("expected the peer clusters to be removed, got %v", cluster.PeerClusterNames)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func testPulsarClusterPeerClustersUnit(url, webServiceURL, peerClusters string) string {
+	if peerClusters != "" {
+		peerClusters = "peer_clusters      = " + peerClusters
+	}
+	return testFakeProvider(url) + fmt.Sprintf(`
+resource "pulsar_cluster" "test" {
+  cluster = "eternals"
+
+  cluster_data {
+    web_service_url    = "%s"
+    broker_service_url = "http://localhost:6050"
+    %s
+  }
+}
+`, webServiceURL, peerClusters)
+}
@@ -25,9 +25,12 @@ import (
 
 	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/rest"
 	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/utils"
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/pkg/errors"
 	"github.com/streamnative/terraform-provider-pulsar/bytesize"
 )
@@ -70,8 +73,55 @@ const (
 	resourceFunctionRAMKey                  = "ram_mb"
 	resourceFunctionDiskKey                 = "disk_mb"
 	resourceFunctionUserConfig              = "user_config"
+	resourceFunctionArchiveSHA256Key        = "archive_sha256"
+	resourceFunctionDesiredStateKey         = "desired_state"
+	resourceFunctionRestartTriggerKey       = "restart_trigger"
+	resourceFunctionWaitForRunningKey       = "wait_for_running"
+	// window config
+	resourceFunctionWindowConfigKey                = "window_config"
+	resourceFunctionWindowLengthCountKey           = "window_length_count"
+	resourceFunctionWindowLengthDurationKey        = "window_length_duration_ms"
+	resourceFunctionSlidingIntervalCountKey        = "sliding_interval_count"
+	resourceFunctionSlidingIntervalDurationKey     = "sliding_interval_duration_ms"
+	resourceFunctionLateDataTopicKey               = "late_data_topic"
+	resourceFunctionMaxLagKey                      = "max_lag_ms"
+	resourceFunctionWatermarkEmitIntervalKey       = "watermark_emit_interval_ms"
+	resourceFunctionTimestampExtractorClassNameKey = "timestamp_extractor_classname"
+	// input specs
+	resourceFunctionInputSpecsKey                  = "input_specs"
+	resourceFunctionInputSpecsTopicKey             = "key"
+	resourceFunctionInputSpecsSchemaTypeKey        = "schema_type"
+	resourceFunctionInputSpecsSerdeClassNameKey    = "serde_class_name"
+	resourceFunctionInputSpecsIsRegexPatternKey    = "is_regex_pattern"
+	resourceFunctionInputSpecsReceiverQueueSizeKey = "receiver_queue_size"
+	resourceFunctionInputSpecsSchemaPropertiesKey  = "schema_properties"
+	resourceFunctionInputSpecsConsumerPropsKey     = "consumer_properties"
+	resourceFunctionInputSpecsPoolMessagesKey      = "pool_messages"
+	resourceFunctionInputSpecsCryptoConfigKey      = "crypto_config"
+	resourceFunctionCryptoKeyReaderClassNameKey    = "crypto_key_reader_classname"
+	resourceFunctionCryptoKeyReaderConfigKey       = "crypto_key_reader_config"
+	resourceFunctionConsumerCryptoFailureActionKey = "consumer_crypto_failure_action"
+	resourceFunctionCustomSchemaInputKey           = "custom_schema_input"
+	resourceFunctionProducerConfigKey              = "producer_config"
+	resourceFunctionSecretKey                      = "secret"
 )
 
+var resourceFunctionInstanceConfigKeys = instanceConfigKeys{
+	archives: []string{resourceFunctionJarKey, resourceFunctionPyKey, resourceFunctionGoKey},
+	inputs: []string{resourceFunctionInputsKey, resourceFunctionTopicsPatternKey, resourceFunctionInputSpecsKey,
+		resourceFunctionCustomSerdeInputsKey, resourceFunctionCustomSchemaInputsKey, resourceFunctionCustomSchemaInputKey},
+	topics: []string{resourceFunctionInputsKey, resourceFunctionInputSpecsKey, resourceFunctionCustomSerdeInputsKey,
+		resourceFunctionCustomSchemaInputsKey, resourceFunctionCustomSchemaInputKey, resourceFunctionOutputKey,
+		resourceFunctionLogTopicKey, resourceFunctionDeadLetterTopicKey},
+	effectivelyOnceConflicts: []string{resourceFunctionRetainKeyOrderingKey, resourceFunctionMaxMessageRetriesKey},
+	processingGuarantees:     resourceFunctionProcessingGuaranteesKey,
+	retainOrdering:           resourceFunctionRetainOrderingKey,
+	retainKeyOrdering:        resourceFunctionRetainKeyOrderingKey,
+	timeout:                  resourceFunctionTimeoutKey,
+	maxMessageRetries:        resourceFunctionMaxMessageRetriesKey,
+	deadLetterTopic:          resourceFunctionDeadLetterTopicKey,
+}
+
 var resourceFunctionDescriptions = make(map[string]string)
 
 func init() {
@@ -90,7 +140,7 @@ func init() {
 		resourceFunctionParallelismKey:          "The parallelism of the function.",
 		resourceFunctionProcessingGuaranteesKey: "The processing guarantees (aka delivery semantics) applied to the function. Possible values are `ATMOST_ONCE`, `ATLEAST_ONCE`, and `EFFECTIVELY_ONCE`.",
 		resourceFunctionSubscriptionNameKey:     "The subscription name of the function.",
-		resourceFunctionSubscriptionPositionKey: "The subscription position of the function. Possible values are `LATEST`, `EARLIEST`, and `CUSTOM`.",
+		resourceFunctionSubscriptionPositionKey: "The subscription position of the function. Possible values are `Earliest` and `Latest`.",
 		resourceFunctionCleanupSubscriptionKey:  "Whether to clean up subscription when the function is deleted.",
 		resourceFunctionSkipToLatestKey:         "Whether to skip to the latest position when the function is restarted after failure.",
 		resourceFunctionForwardSourceMessageKey: "Whether to forward source message property to the function output message.",
@@ -114,6 +164,39 @@ func init() {
 		resourceFunctionRAMKey:                  "The RAM that need to be allocated per function instance",
 		resourceFunctionDiskKey:                 "The disk that need to be allocated per function instance",
 		resourceFunctionUserConfig:              "User-defined config key/values",
+		resourceFunctionArchiveSHA256Key:        "The sha256 of the local archive, used to detect content changes when the archive path stays the same.",
+		resourceFunctionDesiredStateKey:         "The desired state of the function instances. Possible values are `running` and `stopped`, the actual state is read from the instance status.",
+		resourceFunctionRestartTriggerKey:       "Arbitrary key/values that restart the function instances when changed.",
+		resourceFunctionWaitForRunningKey:       "Whether to wait on create and update until all the function instances are running, the apply fails with the last instance error when an instance restarts or raises an exception, or when they do not start within the timeout.",
+
+		// window config
+		resourceFunctionWindowConfigKey:                "The window configuration of a windowed function, the function class has to implement `WindowFunction`.",
+		resourceFunctionWindowLengthCountKey:           "The number of messages per window.",
+		resourceFunctionWindowLengthDurationKey:        "The time duration of a window in milliseconds.",
+		resourceFunctionSlidingIntervalCountKey:        "The number of messages after which the window slides, defaults to the window length count.",
+		resourceFunctionSlidingIntervalDurationKey:     "The time duration after which the window slides in milliseconds, defaults to the window length duration.",
+		resourceFunctionLateDataTopicKey:               "The topic the messages arriving after their window was processed are sent to.",
+		resourceFunctionMaxLagKey:                      "The maximum lateness of messages in milliseconds when a timestamp extractor is set.",
+		resourceFunctionWatermarkEmitIntervalKey:       "The interval in milliseconds at which watermarks are emitted when a timestamp extractor is set.",
+		resourceFunctionTimestampExtractorClassNameKey: "The class name of the timestamp extractor, windows are based on the processing time when not set.",
+
+		// input specs
+		resourceFunctionInputSpecsKey:                  "The consumer configuration of each input topic, supersedes `inputs`, `custom_serde_inputs` and `custom_schema_inputs`.",
+		resourceFunctionInputSpecsTopicKey:             "The input topic, or topics pattern when `is_regex_pattern` is set.",
+		resourceFunctionInputSpecsSchemaTypeKey:        "The schema type or the schema class name used to consume the topic.",
+		resourceFunctionInputSpecsSerdeClassNameKey:    "The SerDe class name used to consume the topic.",
+		resourceFunctionInputSpecsIsRegexPatternKey:    "Whether the key is a regex pattern matching the input topics.",
+		resourceFunctionInputSpecsReceiverQueueSizeKey: "The receiver queue size of the consumer.",
+		resourceFunctionInputSpecsSchemaPropertiesKey:  "The properties of the schema, e.g. the configuration of a JSON or Avro schema.",
+		resourceFunctionInputSpecsConsumerPropsKey:     "The properties of the consumer.",
+		resourceFunctionInputSpecsPoolMessagesKey:      "Whether the consumer uses pooled message buffers.",
+		resourceFunctionInputSpecsCryptoConfigKey:      "The configuration to decrypt the messages of the topic.",
+		resourceFunctionCryptoKeyReaderClassNameKey:    "The class name of the crypto key reader.",
+		resourceFunctionCryptoKeyReaderConfigKey:       "The configuration of the crypto key reader.",
+		resourceFunctionConsumerCryptoFailureActionKey: "The action when a message fails to be decrypted. Possible values are `FAIL`, `DISCARD` and `CONSUME`.",
+		resourceFunctionCustomSchemaInputKey:           "The schema used to consume an input topic, repeated for each topic, conflicts with `custom_schema_inputs`.",
+		resourceFunctionProducerConfigKey:              "The configuration of the producer of the output topic.",
+		resourceFunctionSecretKey:                      "The secrets of the function fetched by the secrets provider, conflicts with `secrets`.",
 	}
 }
 
@@ -123,6 +206,14 @@ func resourcePulsarFunction() *schema.Resource {
 		ReadContext:   resourcePulsarFunctionRead,
 		UpdateContext: resourcePulsarFunctionUpdate,
 		DeleteContext: resourcePulsarFunctionDelete,
+		Timeouts:      lifecycleTimeouts(),
+		CustomizeDiff: customdiff.All(
+			customizeDiffArchiveSHA256(resourceFunctionArchiveSHA256Key, resourceFunctionJarKey, resourceFunctionPyKey,
+				resourceFunctionGoKey),
+			customizeDiffSecret(resourceFunctionSecretKey),
+			customizeDiffInstanceConfig(resourceFunctionInstanceConfigKeys),
+			customizeDiffFunctionWindowConfig,
+		),
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 				id := d.Id()
@@ -140,6 +231,16 @@ func resourcePulsarFunction() *schema.Resource {
 				if diags.HasError() {
 					return nil, fmt.Errorf("import %q: %s", d.Id(), diags[0].Summary)
 				}
+
+				// nothing is configured yet, so all the input specs are imported
+				functionConfig, err := getV3ClientFromMeta(meta).Functions().GetFunction(parts[0], parts[1], parts[2])
+				if err != nil {
+					return nil, fmt.Errorf("import %q: %w", d.Id(), err)
+				}
+				if len(functionConfig.InputSpecs) != 0 {
+					_ = d.Set(resourceFunctionInputSpecsKey, flattenFunctionInputSpecs(functionConfig.InputSpecs,
+						d.Get(resourceFunctionInputSpecsKey).(*schema.Set)))
+				}
 				return []*schema.ResourceData{d}, nil
 			},
 		},
@@ -206,6 +307,11 @@ func resourcePulsarFunction() *schema.Resource {
 				Type:        schema.TypeString,
 				Optional:    true,
 				Description: resourceFunctionDescriptions[resourceFunctionProcessingGuaranteesKey],
+				ValidateFunc: validation.StringInSlice([]string{
+					ProcessingGuaranteesAtLeastOnce,
+					ProcessingGuaranteesAtMostOnce,
+					ProcessingGuaranteesEffectivelyOnce,
+				}, false),
 			},
 			resourceFunctionSubscriptionNameKey: {
 				Type:        schema.TypeString,
@@ -316,14 +422,128 @@ func resourcePulsarFunction() *schema.Resource {
 				Type:        schema.TypeMap,
 				Optional:    true,
 				Description: resourceFunctionDescriptions[resourceFunctionCustomSerdeInputsKey],
+				Deprecated:  "use input_specs instead",
 				Elem:        &schema.Schema{Type: schema.TypeString},
 			},
 			resourceFunctionCustomSchemaInputsKey: {
 				Type:        schema.TypeMap,
 				Optional:    true,
 				Description: resourceFunctionDescriptions[resourceFunctionCustomSchemaInputsKey],
+				Deprecated:  "use custom_schema_input or input_specs instead",
 				Elem:        &schema.Schema{Type: schema.TypeString},
 			},
+			resourceFunctionCustomSchemaInputKey: {
+				Type:          schema.TypeSet,
+				Optional:      true,
+				ConflictsWith: []string{resourceFunctionCustomSchemaInputsKey},
+				Description:   resourceFunctionDescriptions[resourceFunctionCustomSchemaInputKey],
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						resourceFunctionInputSpecsTopicKey: {
+							Type:         schema.TypeString,
+							Required:     true,
+							Description:  resourceFunctionDescriptions[resourceFunctionInputSpecsTopicKey],
+							ValidateFunc: validateNotBlank,
+						},
+						resourceFunctionInputSpecsSchemaTypeKey: {
+							Type:         schema.TypeString,
+							Required:     true,
+							Description:  resourceFunctionDescriptions[resourceFunctionInputSpecsSchemaTypeKey],
+							ValidateFunc: validateNotBlank,
+						},
+						resourceFunctionInputSpecsSchemaPropertiesKey: {
+							Type:        schema.TypeMap,
+							Optional:    true,
+							Description: resourceFunctionDescriptions[resourceFunctionInputSpecsSchemaPropertiesKey],
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+			// terraform doesn't nested map, so use TypeSet.
+			resourceFunctionInputSpecsKey: {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: resourceFunctionDescriptions[resourceFunctionInputSpecsKey],
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						resourceFunctionInputSpecsTopicKey: {
+							Type:         schema.TypeString,
+							Required:     true,
+							Description:  resourceFunctionDescriptions[resourceFunctionInputSpecsTopicKey],
+							ValidateFunc: validateNotBlank,
+						},
+						resourceFunctionInputSpecsSchemaTypeKey: {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: resourceFunctionDescriptions[resourceFunctionInputSpecsSchemaTypeKey],
+						},
+						resourceFunctionInputSpecsSerdeClassNameKey: {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: resourceFunctionDescriptions[resourceFunctionInputSpecsSerdeClassNameKey],
+						},
+						resourceFunctionInputSpecsIsRegexPatternKey: {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Description: resourceFunctionDescriptions[resourceFunctionInputSpecsIsRegexPatternKey],
+						},
+						resourceFunctionInputSpecsReceiverQueueSizeKey: {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Description:  resourceFunctionDescriptions[resourceFunctionInputSpecsReceiverQueueSizeKey],
+							ValidateFunc: validateGtEq0,
+						},
+						resourceFunctionInputSpecsSchemaPropertiesKey: {
+							Type:        schema.TypeMap,
+							Optional:    true,
+							Description: resourceFunctionDescriptions[resourceFunctionInputSpecsSchemaPropertiesKey],
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						resourceFunctionInputSpecsConsumerPropsKey: {
+							Type:        schema.TypeMap,
+							Optional:    true,
+							Description: resourceFunctionDescriptions[resourceFunctionInputSpecsConsumerPropsKey],
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						resourceFunctionInputSpecsPoolMessagesKey: {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Description: resourceFunctionDescriptions[resourceFunctionInputSpecsPoolMessagesKey],
+						},
+						resourceFunctionInputSpecsCryptoConfigKey: {
+							Type:        schema.TypeList,
+							Optional:    true,
+							MaxItems:    1,
+							Description: resourceFunctionDescriptions[resourceFunctionInputSpecsCryptoConfigKey],
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									resourceFunctionCryptoKeyReaderClassNameKey: {
+										Type:         schema.TypeString,
+										Required:     true,
+										Description:  resourceFunctionDescriptions[resourceFunctionCryptoKeyReaderClassNameKey],
+										ValidateFunc: validateNotBlank,
+									},
+									resourceFunctionCryptoKeyReaderConfigKey: {
+										Type:        schema.TypeMap,
+										Optional:    true,
+										Description: resourceFunctionDescriptions[resourceFunctionCryptoKeyReaderConfigKey],
+										Elem:        &schema.Schema{Type: schema.TypeString},
+									},
+									resourceFunctionConsumerCryptoFailureActionKey: {
+										Type:        schema.TypeString,
+										Optional:    true,
+										Description: resourceFunctionDescriptions[resourceFunctionConsumerCryptoFailureActionKey],
+										ValidateFunc: validation.StringInSlice([]string{
+											"FAIL", "DISCARD", "CONSUME",
+										}, false),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
 			resourceFunctionCustomSchemaOutputsKey: {
 				Type:        schema.TypeMap,
 				Optional:    true,
@@ -336,20 +556,14 @@ func resourcePulsarFunction() *schema.Resource {
 				Description: resourceFunctionDescriptions[resourceFunctionCustomRuntimeOptionsKey],
 			},
 			resourceFunctionSecretsKey: {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: resourceFunctionDescriptions[resourceFunctionSecretsKey],
-				ValidateFunc: func(val interface{}, key string) ([]string, []error) {
-					v := val.(string)
-					_, err := json.Marshal(v)
-					if err != nil {
-						return nil, []error{
-							fmt.Errorf("cannot marshal %s: %s", v, err.Error()),
-						}
-					}
-					return nil, nil
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{resourceFunctionSecretKey},
+				Description:   resourceFunctionDescriptions[resourceFunctionSecretsKey],
+				ValidateFunc:  jsonValidateFunc,
 			},
+			resourceFunctionSecretKey: schemaSecret(resourceFunctionDescriptions[resourceFunctionSecretKey],
+				resourceFunctionSecretsKey),
 			resourceFunctionCPUKey: {
 				Type:        schema.TypeFloat,
 				Optional:    true,
@@ -374,10 +588,138 @@ func resourcePulsarFunction() *schema.Resource {
 				Description: resourceFunctionDescriptions[resourceFunctionUserConfig],
 				Elem:        &schema.Schema{Type: schema.TypeString},
 			},
+			resourceFunctionArchiveSHA256Key: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: resourceFunctionDescriptions[resourceFunctionArchiveSHA256Key],
+			},
+			resourceFunctionDesiredStateKey:   schemaDesiredState(resourceFunctionDescriptions[resourceFunctionDesiredStateKey]),
+			resourceFunctionRestartTriggerKey: schemaRestartTrigger(resourceFunctionDescriptions[resourceFunctionRestartTriggerKey]),
+			resourceFunctionWaitForRunningKey: schemaWaitForRunning(resourceFunctionDescriptions[resourceFunctionWaitForRunningKey]),
+			resourceFunctionProducerConfigKey: {
+				Type:             schema.TypeList,
+				Optional:         true,
+				MaxItems:         1,
+				Description:      resourceFunctionDescriptions[resourceFunctionProducerConfigKey],
+				DiffSuppressFunc: suppressFunctionProducerConfigDefaults,
+				Elem: &schema.Resource{
+					Schema: schemaFunctionProducerConfig(),
+				},
+			},
+			resourceFunctionWindowConfigKey: {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: resourceFunctionDescriptions[resourceFunctionWindowConfigKey],
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						resourceFunctionWindowLengthCountKey: {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Description:  resourceFunctionDescriptions[resourceFunctionWindowLengthCountKey],
+							ValidateFunc: validation.IntAtLeast(1),
+						},
+						resourceFunctionWindowLengthDurationKey: {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Description:  resourceFunctionDescriptions[resourceFunctionWindowLengthDurationKey],
+							ValidateFunc: validation.IntAtLeast(1),
+						},
+						resourceFunctionSlidingIntervalCountKey: {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Description:  resourceFunctionDescriptions[resourceFunctionSlidingIntervalCountKey],
+							ValidateFunc: validation.IntAtLeast(1),
+						},
+						resourceFunctionSlidingIntervalDurationKey: {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Description:  resourceFunctionDescriptions[resourceFunctionSlidingIntervalDurationKey],
+							ValidateFunc: validation.IntAtLeast(1),
+						},
+						resourceFunctionLateDataTopicKey: {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: resourceFunctionDescriptions[resourceFunctionLateDataTopicKey],
+						},
+						resourceFunctionMaxLagKey: {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Description:  resourceFunctionDescriptions[resourceFunctionMaxLagKey],
+							ValidateFunc: validateGtEq0,
+						},
+						resourceFunctionWatermarkEmitIntervalKey: {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Description:  resourceFunctionDescriptions[resourceFunctionWatermarkEmitIntervalKey],
+							ValidateFunc: validation.IntAtLeast(1),
+						},
+						resourceFunctionTimestampExtractorClassNameKey: {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: resourceFunctionDescriptions[resourceFunctionTimestampExtractorClassNameKey],
+						},
+					},
+				},
+			},
 		},
 	}
 }
 
+// the compression type the worker sets on the producer of functions when none is configured
+const functionDefaultCompressionType = "LZ4"
+
+// schemaFunctionProducerConfig is the producer config shared with sources, except that the worker reports
+// its default compression type for functions when none is configured
+func schemaFunctionProducerConfig() map[string]*schema.Schema {
+	producerConfig := schemaProducerConfig()
+	producerConfig[producerConfigCompressionTypeKey].DiffSuppressFunc = func(k, old, new string,
+		d *schema.ResourceData) bool {
+		return new == "" && old == functionDefaultCompressionType
+	}
+	return producerConfig
+}
+
+// suppressFunctionProducerConfigDefaults hides the producer config the worker reports for functions without
+// a producer_config block, as long as it only holds the worker defaults
+func suppressFunctionProducerConfigDefaults(k, old, new string, d *schema.ResourceData) bool {
+	raw := d.GetRawConfig()
+	if raw.IsNull() {
+		return false
+	}
+	if configured := raw.GetAttr(resourceFunctionProducerConfigKey); !configured.IsKnown() ||
+		(!configured.IsNull() && configured.LengthInt() != 0) {
+		return false
+	}
+
+	state, _ := d.GetChange(resourceFunctionProducerConfigKey)
+	items := state.([]interface{})
+	if len(items) == 0 || items[0] == nil {
+		return true
+	}
+	for key, value := range items[0].(map[string]interface{}) {
+		switch value := value.(type) {
+		case string:
+			if value != "" && !(key == producerConfigCompressionTypeKey && value == functionDefaultCompressionType) {
+				return false
+			}
+		case int:
+			if value != 0 {
+				return false
+			}
+		case bool:
+			if value {
+				return false
+			}
+		case *schema.Set:
+			if value.Len() != 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 func resourcePulsarFunctionRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := getV3ClientFromMeta(meta).Functions()
 
@@ -401,6 +743,13 @@ func resourcePulsarFunctionRead(ctx context.Context, d *schema.ResourceData, met
 		return diag.Errorf("ERROR_UNMARSHAL_FUNCTION_CONFIG: %v", err)
 	}
 
+	state, err := readLifecycleState(functionLifecycleClient(meta, tenant, namespace, name),
+		d.Get(resourceFunctionDesiredStateKey).(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	_ = d.Set(resourceFunctionDesiredStateKey, state)
+
 	return nil
 }
 
@@ -422,6 +771,11 @@ func resourcePulsarFunctionCreate(ctx context.Context, d *schema.ResourceData, m
 		archive = *functionConfig.Go
 	}
 
+	archiveSum, err := archiveSHA256(archive)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("ERROR_READ_ARCHIVE: %w", err))
+	}
+
 	if isPackageURLSupported(archive) {
 		err = client.CreateFuncWithURL(functionConfig, archive)
 	} else {
@@ -433,40 +787,106 @@ func resourcePulsarFunctionCreate(ctx context.Context, d *schema.ResourceData, m
 	}
 	tflog.Debug(ctx, "@@@Create function: success")
 
-	return resourcePulsarFunctionRead(ctx, d, meta)
+	// the function exists from now on, a failed wait taints it instead of leaving it out of the state
+	d.SetId(fmt.Sprintf("%s/%s/%s", functionConfig.Tenant, functionConfig.Namespace, functionConfig.Name))
+	_ = d.Set(resourceFunctionArchiveSHA256Key, archiveSum)
+
+	return applyFunctionLifecycle(ctx, d, meta)
 }
 
 func resourcePulsarFunctionUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := getV3ClientFromMeta(meta).Functions()
 
-	functionConfig, err := marshalFunctionConfig(d)
-	if err != nil {
-		return diag.FromErr(err)
-	}
+	if d.HasChangesExcept(resourceFunctionDesiredStateKey, resourceFunctionRestartTriggerKey,
+		resourceFunctionWaitForRunningKey) {
+		functionConfig, err := marshalFunctionConfig(d)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		resetRemovedFunctionInputs(d, functionConfig)
+
+		var archive string
+		switch {
+		case functionConfig.Jar != nil:
+			archive = *functionConfig.Jar
+		case functionConfig.Py != nil:
+			archive = *functionConfig.Py
+		case functionConfig.Go != nil:
+			archive = *functionConfig.Go
+		}
 
-	var archive string
-	switch {
-	case functionConfig.Jar != nil:
-		archive = *functionConfig.Jar
-	case functionConfig.Py != nil:
-		archive = *functionConfig.Py
-	case functionConfig.Go != nil:
-		archive = *functionConfig.Go
-	}
+		archiveSum, err := archiveSHA256(archive)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("ERROR_READ_ARCHIVE: %w", err))
+		}
 
-	updateOptions := utils.NewUpdateOptions()
-	if isPackageURLSupported(archive) {
-		err = client.UpdateFunctionWithURL(functionConfig, archive, updateOptions)
-	} else {
-		err = client.UpdateFunction(functionConfig, archive, updateOptions)
+		updateOptions := utils.NewUpdateOptions()
+		if isPackageURLSupported(archive) {
+			err = client.UpdateFunctionWithURL(functionConfig, archive, updateOptions)
+		} else {
+			err = client.UpdateFunction(functionConfig, archive, updateOptions)
+		}
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		_ = d.Set(resourceFunctionArchiveSHA256Key, archiveSum)
 	}
+
+	return applyFunctionLifecycle(ctx, d, meta)
+}
+
+// applyFunctionLifecycle brings the function instances to the desired state and reads the function back
+func applyFunctionLifecycle(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tenant := d.Get(resourceFunctionTenantKey).(string)
+	namespace := d.Get(resourceFunctionNamespaceKey).(string)
+	name := d.Get(resourceFunctionNameKey).(string)
+
+	lifecycle := functionLifecycleClient(meta, tenant, namespace, name)
+
+	err := applyLifecycle(d, lifecycle, resourceFunctionDesiredStateKey, resourceFunctionRestartTriggerKey)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
+	desiredState := d.Get(resourceFunctionDesiredStateKey).(string)
+	if desiredState == DesiredStateRunning && d.Get(resourceFunctionWaitForRunningKey).(bool) {
+		timeout := d.Timeout(schema.TimeoutUpdate)
+		if d.IsNewResource() {
+			timeout = d.Timeout(schema.TimeoutCreate)
+		}
+		err = waitForRunning(ctx, lifecycle, d.Get(resourceFunctionParallelismKey).(int), timeout)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	return resourcePulsarFunctionRead(ctx, d, meta)
 }
 
+func functionLifecycleClient(meta interface{}, tenant, namespace, name string) lifecycleClient {
+	client := getV3ClientFromMeta(meta).Functions()
+
+	return lifecycleClient{
+		start:   func() error { return client.StartFunction(tenant, namespace, name) },
+		stop:    func() error { return client.StopFunction(tenant, namespace, name) },
+		restart: func() error { return client.RestartFunction(tenant, namespace, name) },
+		status: func() (*instancesStatus, error) {
+			status, err := client.GetFunctionStatus(tenant, namespace, name)
+			if err != nil {
+				return nil, err
+			}
+
+			result := &instancesStatus{numInstances: status.NumInstances, numRunning: status.NumRunning}
+			for _, instance := range status.Instances {
+				result.addInstance(instance.Status.Running, instance.Status.Err, instance.Status.NumRestarts,
+					instance.Status.LatestUserExceptions, instance.Status.LatestSystemExceptions)
+			}
+			return result, nil
+		},
+	}
+}
+
 func resourcePulsarFunctionDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := getV3ClientFromMeta(meta).Functions()
 
@@ -627,6 +1047,14 @@ func marshalFunctionConfig(d *schema.ResourceData) (*utils.FunctionConfig, error
 		functionConfig.CustomSchemaInputs = stringMap
 	}
 
+	if inter, ok := d.GetOk(resourceFunctionCustomSchemaInputKey); ok {
+		customSchemaInputs, err := marshalFunctionCustomSchemaInputs(inter.(*schema.Set))
+		if err != nil {
+			return nil, err
+		}
+		functionConfig.CustomSchemaInputs = customSchemaInputs
+	}
+
 	if inter, ok := d.GetOk(resourceFunctionCustomSchemaOutputsKey); ok {
 		interMap := inter.(map[string]interface{})
 		stringMap := make(map[string]string, len(interMap))
@@ -653,6 +1081,10 @@ func marshalFunctionConfig(d *schema.ResourceData) (*utils.FunctionConfig, error
 		functionConfig.Secrets = secrets
 	}
 
+	if inter, ok := d.GetOk(resourceFunctionSecretKey); ok {
+		functionConfig.Secrets = marshalSecret(inter.(*schema.Set))
+	}
+
 	resources := utils.NewDefaultResources()
 
 	if inter, ok := d.GetOk(resourceFunctionCPUKey); ok {
@@ -674,9 +1106,231 @@ func marshalFunctionConfig(d *schema.ResourceData) (*utils.FunctionConfig, error
 		functionConfig.UserConfig = interMap
 	}
 
+	if inter, ok := d.GetOk(resourceFunctionInputSpecsKey); ok {
+		functionConfig.InputSpecs = marshalFunctionInputSpecs(inter.(*schema.Set))
+	}
+
+	// the worker keeps the producer config when none is sent, an empty one resets it to the defaults
+	functionConfig.ProducerConfig = &utils.ProducerConfig{}
+	if inter, ok := d.GetOk(resourceFunctionProducerConfigKey); ok {
+		if items := inter.([]interface{}); len(items) > 0 && items[0] != nil {
+			producerConfig, err := marshalProducerConfig(
+				getProducerConfigBlockValue(items[0].(map[string]interface{})))
+			if err != nil {
+				return nil, err
+			}
+			// an empty crypto config would make the worker load a crypto key reader without class name
+			if producerConfig.CryptoConfig.CryptoKeyReaderClassName == "" {
+				producerConfig.CryptoConfig = nil
+			}
+			functionConfig.ProducerConfig = producerConfig
+		}
+	}
+
+	if inter, ok := d.GetOk(resourceFunctionWindowConfigKey); ok {
+		functionConfig.WindowConfig = marshalWindowConfig(inter.([]interface{}), d.GetRawConfig())
+	}
+
 	return functionConfig, nil
 }
 
+func marshalFunctionInputSpecs(set *schema.Set) map[string]utils.ConsumerConfig {
+	inputSpecs := make(map[string]utils.ConsumerConfig)
+
+	for _, item := range set.List() {
+		data := item.(map[string]interface{})
+
+		consumerConfig := utils.ConsumerConfig{
+			SchemaType:         data[resourceFunctionInputSpecsSchemaTypeKey].(string),
+			SerdeClassName:     data[resourceFunctionInputSpecsSerdeClassNameKey].(string),
+			RegexPattern:       data[resourceFunctionInputSpecsIsRegexPatternKey].(bool),
+			ReceiverQueueSize:  data[resourceFunctionInputSpecsReceiverQueueSizeKey].(int),
+			SchemaProperties:   toStringMap(data[resourceFunctionInputSpecsSchemaPropertiesKey].(map[string]interface{})),
+			ConsumerProperties: toStringMap(data[resourceFunctionInputSpecsConsumerPropsKey].(map[string]interface{})),
+			PoolMessages:       data[resourceFunctionInputSpecsPoolMessagesKey].(bool),
+		}
+
+		if cryptoItems := data[resourceFunctionInputSpecsCryptoConfigKey].([]interface{}); len(cryptoItems) > 0 &&
+			cryptoItems[0] != nil {
+			cryptoData := cryptoItems[0].(map[string]interface{})
+			consumerConfig.CryptoConfig = &utils.CryptoConfig{
+				CryptoKeyReaderClassName:    cryptoData[resourceFunctionCryptoKeyReaderClassNameKey].(string),
+				CryptoKeyReaderConfig:       cryptoData[resourceFunctionCryptoKeyReaderConfigKey].(map[string]interface{}),
+				ConsumerCryptoFailureAction: cryptoData[resourceFunctionConsumerCryptoFailureActionKey].(string),
+			}
+		}
+
+		inputSpecs[data[resourceFunctionInputSpecsTopicKey].(string)] = consumerConfig
+	}
+
+	return inputSpecs
+}
+
+// marshalFunctionCustomSchemaInputs converts the custom_schema_input blocks, the schema properties are
+// given to the worker along with the schema type as a consumer config serialized in JSON
+func marshalFunctionCustomSchemaInputs(set *schema.Set) (map[string]string, error) {
+	customSchemaInputs := make(map[string]string, set.Len())
+	for _, item := range set.List() {
+		data := item.(map[string]interface{})
+		topic := data[resourceFunctionInputSpecsTopicKey].(string)
+		schemaType := data[resourceFunctionInputSpecsSchemaTypeKey].(string)
+		// a block removed along with its schema properties is read back empty during the update
+		if topic == "" {
+			continue
+		}
+
+		properties := toStringMap(data[resourceFunctionInputSpecsSchemaPropertiesKey].(map[string]interface{}))
+		if len(properties) == 0 {
+			customSchemaInputs[topic] = schemaType
+			continue
+		}
+
+		consumerConfig, err := json.Marshal(utils.ConsumerConfig{SchemaType: schemaType, SchemaProperties: properties})
+		if err != nil {
+			return nil, fmt.Errorf("ERROR_MARSHAL_CUSTOM_SCHEMA_INPUT: %w", err)
+		}
+		customSchemaInputs[topic] = string(consumerConfig)
+	}
+	return customSchemaInputs, nil
+}
+
+// resetRemovedFunctionInputs resets the consumer configuration of the topics removed from input_specs
+// and custom_schema_input. The worker merges them by topic on update, so leaving them out of the update
+// would keep them on the worker.
+func resetRemovedFunctionInputs(d *schema.ResourceData, functionConfig *utils.FunctionConfig) {
+	oldInputSpecs, _ := d.GetChange(resourceFunctionInputSpecsKey)
+	for _, item := range oldInputSpecs.(*schema.Set).List() {
+		data := item.(map[string]interface{})
+		topic := data[resourceFunctionInputSpecsTopicKey].(string)
+		if _, ok := functionConfig.InputSpecs[topic]; ok {
+			continue
+		}
+		if functionConfig.InputSpecs == nil {
+			functionConfig.InputSpecs = make(map[string]utils.ConsumerConfig)
+		}
+		functionConfig.InputSpecs[topic] = utils.ConsumerConfig{
+			RegexPattern: data[resourceFunctionInputSpecsIsRegexPatternKey].(bool),
+		}
+	}
+
+	oldCustomSchemaInputs, _ := d.GetChange(resourceFunctionCustomSchemaInputKey)
+	for _, item := range oldCustomSchemaInputs.(*schema.Set).List() {
+		topic := item.(map[string]interface{})[resourceFunctionInputSpecsTopicKey].(string)
+		if _, ok := functionConfig.CustomSchemaInputs[topic]; ok {
+			continue
+		}
+		if functionConfig.CustomSchemaInputs == nil {
+			functionConfig.CustomSchemaInputs = make(map[string]string)
+		}
+		// an empty schema type consumes the topic with the default schema
+		functionConfig.CustomSchemaInputs[topic] = ""
+	}
+}
+
+// marshalWindowConfig sends the attributes which are configured, the zero values included
+func marshalWindowConfig(items []interface{}, rawConfig cty.Value) *utils.WindowConfig {
+	windowConfig := utils.NewDefaultWindowConfing()
+	if len(items) == 0 || items[0] == nil {
+		return windowConfig
+	}
+	data := items[0].(map[string]interface{})
+
+	configured := rawConfigWindowConfig(rawConfig)
+	isConfigured := func(key string) bool {
+		if configured.IsNull() {
+			return data[key].(int) != 0
+		}
+		return !configured.GetAttr(key).IsNull()
+	}
+
+	if isConfigured(resourceFunctionWindowLengthCountKey) {
+		v := data[resourceFunctionWindowLengthCountKey].(int)
+		windowConfig.WindowLengthCount = &v
+	}
+	if isConfigured(resourceFunctionWindowLengthDurationKey) {
+		v := int64(data[resourceFunctionWindowLengthDurationKey].(int))
+		windowConfig.WindowLengthDurationMs = &v
+	}
+	if isConfigured(resourceFunctionSlidingIntervalCountKey) {
+		v := data[resourceFunctionSlidingIntervalCountKey].(int)
+		windowConfig.SlidingIntervalCount = &v
+	}
+	if isConfigured(resourceFunctionSlidingIntervalDurationKey) {
+		v := int64(data[resourceFunctionSlidingIntervalDurationKey].(int))
+		windowConfig.SlidingIntervalDurationMs = &v
+	}
+	if v := data[resourceFunctionLateDataTopicKey].(string); v != "" {
+		windowConfig.LateDataTopic = &v
+	}
+	if v := data[resourceFunctionTimestampExtractorClassNameKey].(string); v != "" {
+		windowConfig.TimestampExtractorClassName = &v
+	}
+	if isConfigured(resourceFunctionMaxLagKey) {
+		v := int64(data[resourceFunctionMaxLagKey].(int))
+		windowConfig.MaxLagMs = &v
+	}
+	if isConfigured(resourceFunctionWatermarkEmitIntervalKey) {
+		v := int64(data[resourceFunctionWatermarkEmitIntervalKey].(int))
+		windowConfig.WatermarkEmitIntervalMs = &v
+	}
+
+	return windowConfig
+}
+
+// customizeDiffFunctionWindowConfig reports at plan time the window configurations the worker would reject,
+// values which are not known yet count as set
+func customizeDiffFunctionWindowConfig(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	configured := rawConfigWindowConfig(d.GetRawConfig())
+	if configured.IsNull() {
+		return nil
+	}
+	isSet := func(key string) bool {
+		return !configured.GetAttr(key).IsNull()
+	}
+
+	switch {
+	case !isSet(resourceFunctionWindowLengthCountKey) && !isSet(resourceFunctionWindowLengthDurationKey):
+		return fmt.Errorf("ERROR_INVALID_WINDOW_CONFIG: either %s or %s is required",
+			resourceFunctionWindowLengthCountKey, resourceFunctionWindowLengthDurationKey)
+	case isSet(resourceFunctionWindowLengthCountKey) && isSet(resourceFunctionWindowLengthDurationKey):
+		return fmt.Errorf("ERROR_INVALID_WINDOW_CONFIG: %s and %s are mutually exclusive",
+			resourceFunctionWindowLengthCountKey, resourceFunctionWindowLengthDurationKey)
+	case isSet(resourceFunctionSlidingIntervalCountKey) && isSet(resourceFunctionSlidingIntervalDurationKey):
+		return fmt.Errorf("ERROR_INVALID_WINDOW_CONFIG: %s and %s are mutually exclusive",
+			resourceFunctionSlidingIntervalCountKey, resourceFunctionSlidingIntervalDurationKey)
+	}
+
+	// lateness and watermarks only apply to event time windows
+	if !isSet(resourceFunctionTimestampExtractorClassNameKey) {
+		for _, key := range []string{resourceFunctionMaxLagKey, resourceFunctionWatermarkEmitIntervalKey} {
+			if isSet(key) {
+				return fmt.Errorf("ERROR_INVALID_WINDOW_CONFIG: %s requires %s", key,
+					resourceFunctionTimestampExtractorClassNameKey)
+			}
+		}
+	}
+
+	return nil
+}
+
+// rawConfigWindowConfig returns the configured window_config block, null when it is not configured
+func rawConfigWindowConfig(raw cty.Value) cty.Value {
+	if raw.IsNull() || !raw.IsKnown() {
+		return cty.NullVal(cty.DynamicPseudoType)
+	}
+
+	windowConfig := raw.GetAttr(resourceFunctionWindowConfigKey)
+	if windowConfig.IsNull() || !windowConfig.IsKnown() || windowConfig.LengthInt() == 0 {
+		return cty.NullVal(cty.DynamicPseudoType)
+	}
+
+	block := windowConfig.Index(cty.NumberIntVal(0))
+	if !block.IsKnown() {
+		return cty.NullVal(cty.DynamicPseudoType)
+	}
+	return block
+}
+
 func unmarshalFunctionConfig(functionConfig utils.FunctionConfig, d *schema.ResourceData) error {
 	if functionConfig.Jar != nil {
 		err := d.Set(resourceFunctionJarKey, *functionConfig.Jar)
@@ -862,15 +1516,17 @@ func unmarshalFunctionConfig(functionConfig utils.FunctionConfig, d *schema.Reso
 		}
 	}
 
-	if len(functionConfig.CustomSchemaInputs) != 0 {
+	if _, ok := d.GetOk(resourceFunctionCustomSchemaInputsKey); ok {
 		customSchemaInputs := make(map[string]interface{}, len(functionConfig.CustomSchemaInputs))
 		for key, value := range functionConfig.CustomSchemaInputs {
 			customSchemaInputs[key] = value
 		}
 		err = d.Set(resourceFunctionCustomSchemaInputsKey, customSchemaInputs)
-		if err != nil {
-			return err
-		}
+	} else {
+		err = d.Set(resourceFunctionCustomSchemaInputKey, flattenFunctionCustomSchemaInputs(functionConfig.CustomSchemaInputs))
+	}
+	if err != nil {
+		return err
 	}
 
 	if len(functionConfig.CustomSchemaOutputs) != 0 {
@@ -899,13 +1555,21 @@ func unmarshalFunctionConfig(functionConfig utils.FunctionConfig, d *schema.Reso
 	}
 
 	if len(functionConfig.Secrets) != 0 {
-		s, err := json.Marshal(functionConfig.Secrets)
-		if err != nil {
-			return err
-		}
-		err = d.Set(resourceFunctionSecretsKey, string(s))
-		if err != nil {
-			return err
+		secret, structured := flattenSecret(functionConfig.Secrets)
+		if _, ok := d.GetOk(resourceFunctionSecretsKey); !ok && structured {
+			err = d.Set(resourceFunctionSecretKey, secret)
+			if err != nil {
+				return err
+			}
+		} else {
+			s, err := json.Marshal(functionConfig.Secrets)
+			if err != nil {
+				return err
+			}
+			err = d.Set(resourceFunctionSecretsKey, string(s))
+			if err != nil {
+				return err
+			}
 		}
 	}
 
@@ -929,6 +1593,10 @@ func unmarshalFunctionConfig(functionConfig utils.FunctionConfig, d *schema.Reso
 	if len(functionConfig.UserConfig) != 0 {
 		userConfig := make(map[string]interface{}, len(functionConfig.UserConfig))
 		for key, value := range functionConfig.UserConfig {
+			// the window config is also carried in the user config by older workers
+			if key == utils.WindowConfigKey {
+				continue
+			}
 			userConfig[key] = value
 		}
 		err = d.Set(resourceFunctionUserConfig, userConfig)
@@ -937,5 +1605,157 @@ func unmarshalFunctionConfig(functionConfig utils.FunctionConfig, d *schema.Reso
 		}
 	}
 
+	// the worker also holds the specs of the topics given otherwise, only the configured ones are read back
+	inputSpecs := []interface{}{}
+	if configured := d.Get(resourceFunctionInputSpecsKey).(*schema.Set); configured.Len() != 0 {
+		inputSpecs = flattenFunctionInputSpecs(functionConfig.InputSpecs, configured)
+	}
+	err = d.Set(resourceFunctionInputSpecsKey, inputSpecs)
+	if err != nil {
+		return err
+	}
+
+	if functionConfig.ProducerConfig != nil {
+		var producerConfig map[string]interface{}
+		producerConfig, err = flattenProducerConfig(functionConfig.ProducerConfig)
+		if err != nil {
+			return err
+		}
+		err = d.Set(resourceFunctionProducerConfigKey, []interface{}{producerConfig})
+	} else {
+		err = d.Set(resourceFunctionProducerConfigKey, nil)
+	}
+	if err != nil {
+		return err
+	}
+
+	if functionConfig.WindowConfig != nil {
+		err = d.Set(resourceFunctionWindowConfigKey, flattenWindowConfig(functionConfig.WindowConfig,
+			d.Get(resourceFunctionWindowConfigKey).([]interface{})))
+	} else {
+		err = d.Set(resourceFunctionWindowConfigKey, nil)
+	}
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
+
+// flattenFunctionInputSpecs converts the input specs returned by the worker, which also holds the topics
+// of `inputs`, only the configured topics are reflected unless no input specs are configured on import
+func flattenFunctionInputSpecs(inputSpecs map[string]utils.ConsumerConfig, configured *schema.Set) []interface{} {
+	configuredSpecs := make(map[string]map[string]interface{})
+	for _, item := range configured.List() {
+		data := item.(map[string]interface{})
+		configuredSpecs[data[resourceFunctionInputSpecsTopicKey].(string)] = data
+	}
+
+	result := make([]interface{}, 0, len(inputSpecs))
+	for topic, consumerConfig := range inputSpecs {
+		configuredSpec, found := configuredSpecs[topic]
+		if len(configuredSpecs) != 0 && !found {
+			continue
+		}
+
+		data := map[string]interface{}{
+			resourceFunctionInputSpecsTopicKey:             topic,
+			resourceFunctionInputSpecsSchemaTypeKey:        consumerConfig.SchemaType,
+			resourceFunctionInputSpecsSerdeClassNameKey:    consumerConfig.SerdeClassName,
+			resourceFunctionInputSpecsIsRegexPatternKey:    consumerConfig.RegexPattern,
+			resourceFunctionInputSpecsReceiverQueueSizeKey: consumerConfig.ReceiverQueueSize,
+			resourceFunctionInputSpecsSchemaPropertiesKey:  flattenStringMap(consumerConfig.SchemaProperties),
+			resourceFunctionInputSpecsConsumerPropsKey:     flattenStringMap(consumerConfig.ConsumerProperties),
+			resourceFunctionInputSpecsPoolMessagesKey:      consumerConfig.PoolMessages,
+		}
+		// older workers don't return the consumer properties
+		if len(consumerConfig.ConsumerProperties) == 0 && found {
+			data[resourceFunctionInputSpecsConsumerPropsKey] = configuredSpec[resourceFunctionInputSpecsConsumerPropsKey]
+		}
+
+		if consumerConfig.CryptoConfig != nil {
+			keyReaderConfig := make(map[string]interface{}, len(consumerConfig.CryptoConfig.CryptoKeyReaderConfig))
+			for k, v := range consumerConfig.CryptoConfig.CryptoKeyReaderConfig {
+				keyReaderConfig[k] = fmt.Sprintf("%v", v)
+			}
+			data[resourceFunctionInputSpecsCryptoConfigKey] = []interface{}{
+				map[string]interface{}{
+					resourceFunctionCryptoKeyReaderClassNameKey:    consumerConfig.CryptoConfig.CryptoKeyReaderClassName,
+					resourceFunctionCryptoKeyReaderConfigKey:       keyReaderConfig,
+					resourceFunctionConsumerCryptoFailureActionKey: consumerConfig.CryptoConfig.ConsumerCryptoFailureAction,
+				},
+			}
+		}
+
+		result = append(result, data)
+	}
+
+	return result
+}
+
+// flattenFunctionCustomSchemaInputs converts the custom schema inputs returned by the worker, which are
+// either a schema type or a consumer config serialized in JSON. The topics reset to the default schema
+// are left out.
+func flattenFunctionCustomSchemaInputs(customSchemaInputs map[string]string) []interface{} {
+	result := make([]interface{}, 0, len(customSchemaInputs))
+	for topic, value := range customSchemaInputs {
+		consumerConfig := utils.ConsumerConfig{SchemaType: value}
+		if strings.HasPrefix(strings.TrimSpace(value), "{") {
+			consumerConfig = utils.ConsumerConfig{}
+			if err := json.Unmarshal([]byte(value), &consumerConfig); err != nil {
+				consumerConfig.SchemaType = value
+			}
+		}
+		if consumerConfig.SchemaType == "" {
+			continue
+		}
+
+		result = append(result, map[string]interface{}{
+			resourceFunctionInputSpecsTopicKey:            topic,
+			resourceFunctionInputSpecsSchemaTypeKey:       consumerConfig.SchemaType,
+			resourceFunctionInputSpecsSchemaPropertiesKey: flattenStringMap(consumerConfig.SchemaProperties),
+		})
+	}
+	return result
+}
+
+// flattenWindowConfig converts the window config returned by the worker, the sliding intervals, the lag
+// and the watermark interval are defaulted by the worker and only reflected when they were configured,
+// or when nothing is configured yet, e.g. on import
+func flattenWindowConfig(windowConfig *utils.WindowConfig, configured []interface{}) []interface{} {
+	isConfigured := func(key string) bool {
+		if len(configured) == 0 || configured[0] == nil {
+			return true
+		}
+		return configured[0].(map[string]interface{})[key].(int) != 0
+	}
+
+	data := make(map[string]interface{})
+
+	if windowConfig.WindowLengthCount != nil {
+		data[resourceFunctionWindowLengthCountKey] = *windowConfig.WindowLengthCount
+	}
+	if windowConfig.WindowLengthDurationMs != nil {
+		data[resourceFunctionWindowLengthDurationKey] = int(*windowConfig.WindowLengthDurationMs)
+	}
+	if windowConfig.SlidingIntervalCount != nil && isConfigured(resourceFunctionSlidingIntervalCountKey) {
+		data[resourceFunctionSlidingIntervalCountKey] = *windowConfig.SlidingIntervalCount
+	}
+	if windowConfig.SlidingIntervalDurationMs != nil && isConfigured(resourceFunctionSlidingIntervalDurationKey) {
+		data[resourceFunctionSlidingIntervalDurationKey] = int(*windowConfig.SlidingIntervalDurationMs)
+	}
+	if windowConfig.LateDataTopic != nil {
+		data[resourceFunctionLateDataTopicKey] = *windowConfig.LateDataTopic
+	}
+	if windowConfig.MaxLagMs != nil && isConfigured(resourceFunctionMaxLagKey) {
+		data[resourceFunctionMaxLagKey] = int(*windowConfig.MaxLagMs)
+	}
+	if windowConfig.WatermarkEmitIntervalMs != nil && isConfigured(resourceFunctionWatermarkEmitIntervalKey) {
+		data[resourceFunctionWatermarkEmitIntervalKey] = int(*windowConfig.WatermarkEmitIntervalMs)
+	}
+	if windowConfig.TimestampExtractorClassName != nil {
+		data[resourceFunctionTimestampExtractorClassNameKey] = *windowConfig.TimestampExtractorClassName
+	}
+
+	return []interface{}{data}
+}
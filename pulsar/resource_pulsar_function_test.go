@@ -18,14 +18,19 @@
 package pulsar
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
+	"regexp"
 	"strings"
 	"testing"
 
+	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/admin/config"
 	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/rest"
 	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/utils"
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/stretchr/testify/assert"
@@ -33,6 +38,20 @@ import (
 
 func init() {
 	initTestWebServiceURL()
+
+	resource.AddTestSweepers("pulsar_function", &resource.Sweeper{
+		Name: "pulsar_function",
+		F:    testSweepFunctions,
+	})
+}
+
+func testSweepFunctions(url string) error {
+	client, err := sharedClientWithVersion(url, config.V3)
+	if err != nil {
+		return fmt.Errorf("ERROR_GETTING_PULSAR_CLIENT: %w", err)
+	}
+
+	return sweepInstances(url, "FUNCTION", client.Functions().GetFunctions, client.Functions().DeleteFunction)
 }
 
 func TestFunction(t *testing.T) {
@@ -77,10 +96,48 @@ func TestFunction(t *testing.T) {
 					return nil
 				}),
 			},
+			{
+				Config: strings.Replace(string(configBytes), "parallelism = 1",
+					"parallelism = 1\n    desired_state = \"stopped\"", 1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("pulsar_function.function-1", "desired_state", "stopped"),
+					testPulsarFunctionNumRunning("pulsar_function.function-1", 0),
+				),
+			},
+			{
+				Config: strings.Replace(string(configBytes), "parallelism = 1",
+					"parallelism = 1\n    wait_for_running = true", 1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("pulsar_function.function-1", "desired_state", "running"),
+					testPulsarFunctionNumRunning("pulsar_function.function-1", 1),
+				),
+			},
 		},
 	})
 }
 
+func testPulsarFunctionNumRunning(name string, numRunning int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("%s not be found", name)
+		}
+
+		parts := strings.Split(rs.Primary.ID, "/")
+		status, err := getV3ClientFromMeta(testAccProvider.Meta()).Functions().
+			GetFunctionStatus(parts[0], parts[1], parts[2])
+		if err != nil {
+			return err
+		}
+
+		if status.NumRunning != numRunning {
+			return fmt.Errorf("expected %d running instances of %s, got %d", numRunning, rs.Primary.ID, status.NumRunning)
+		}
+
+		return nil
+	}
+}
+
 func testPulsarFunctionDestroy(s *terraform.State) error {
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "pulsar_function" {
@@ -117,3 +174,872 @@ func getPulsarFunctionByResourceID(id string) (*utils.FunctionConfig, error) {
 
 	return &resp, nil
 }
+
+func TestFunctionWithWindowConfig(t *testing.T) {
+	resourceName := "pulsar_function.window"
+	name := testAccRandomName()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testPulsarFunctionDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testPulsarFunctionWithWindowConfig(testWebServiceURL, name, 10),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "window_config.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "window_config.0.window_length_count", "10"),
+					resource.TestCheckResourceAttr(resourceName, "window_config.0.sliding_interval_count", "0"),
+					resource.TestCheckResourceAttr(resourceName, "window_config.0.late_data_topic",
+						"public/default/late-"+name),
+				),
+			},
+			{
+				Config: testPulsarFunctionWithWindowConfig(testWebServiceURL, name, 20),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "window_config.0.window_length_count", "20"),
+					testPulsarFunctionWindowLength(resourceName, 20),
+				),
+			},
+		},
+	})
+}
+
+func testPulsarFunctionWithWindowConfig(url, name string, windowLength int) string {
+	return fmt.Sprintf(`
+provider "pulsar" {
+  web_service_url = "%s"
+}
+
+resource "pulsar_function" "window" {
+  name        = "%s"
+  tenant      = "public"
+  namespace   = "default"
+  parallelism = 1
+  jar         = "function://public/default/api-examples@v1"
+  classname   = "org.apache.pulsar.functions.api.examples.AddWindowFunction"
+  inputs      = ["public/default/in-%s"]
+  output      = "public/default/out-%s"
+
+  window_config {
+    window_length_count = %d
+    late_data_topic     = "public/default/late-%s"
+  }
+}
+`, url, name, name, name, windowLength, name)
+}
+
+func testPulsarFunctionWindowLength(name string, windowLength int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("%s not be found", name)
+		}
+
+		config, err := getPulsarFunctionByResourceID(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		if config.WindowConfig == nil || config.WindowConfig.WindowLengthCount == nil {
+			return fmt.Errorf("window config of %s not found", rs.Primary.ID)
+		}
+		if *config.WindowConfig.WindowLengthCount != windowLength {
+			return fmt.Errorf("expected window length %d, got %d", windowLength, *config.WindowConfig.WindowLengthCount)
+		}
+		// the sliding interval is defaulted to the window length by the worker
+		if config.WindowConfig.SlidingIntervalCount == nil || *config.WindowConfig.SlidingIntervalCount != windowLength {
+			return fmt.Errorf("expected sliding interval %d of %s", windowLength, rs.Primary.ID)
+		}
+
+		return nil
+	}
+}
+
+func TestFunctionWithInputSpecs(t *testing.T) {
+	resourceName := "pulsar_function.input_specs"
+	name := testAccRandomName()
+	topic := "persistent://public/default/in-" + name
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testPulsarFunctionDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testPulsarFunctionWithInputSpecs(testWebServiceURL, name, topic, 100),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "input_specs.#", "1"),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "input_specs.*", map[string]string{
+						"key":                 topic,
+						"schema_type":         "STRING",
+						"receiver_queue_size": "100",
+					}),
+				),
+			},
+			{
+				Config: testPulsarFunctionWithInputSpecs(testWebServiceURL, name, topic, 200),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "input_specs.*", map[string]string{
+						"key":                 topic,
+						"receiver_queue_size": "200",
+					}),
+				),
+			},
+		},
+	})
+}
+
+func testPulsarFunctionWithInputSpecs(url, name, topic string, receiverQueueSize int) string {
+	return fmt.Sprintf(`
+provider "pulsar" {
+  web_service_url = "%s"
+}
+
+resource "pulsar_function" "input_specs" {
+  name        = "%s"
+  tenant      = "public"
+  namespace   = "default"
+  parallelism = 1
+  jar         = "function://public/default/api-examples@v1"
+  classname   = "org.apache.pulsar.functions.api.examples.ExclamationFunction"
+  output      = "public/default/out-%s"
+
+  input_specs {
+    key                 = "%s"
+    schema_type         = "STRING"
+    receiver_queue_size = %d
+
+    consumer_properties = {
+      "ackTimeoutMillis" = "30000"
+    }
+  }
+}
+`, url, name, name, topic, receiverQueueSize)
+}
+
+func TestFunctionWithProducerConfig(t *testing.T) {
+	resourceName := "pulsar_function.producer_config"
+	name := testAccRandomName()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testPulsarFunctionDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testPulsarFunctionWithProducerConfig(testWebServiceURL, name, 500),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "producer_config.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "producer_config.0.max_pending_messages", "500"),
+					resource.TestCheckResourceAttr(resourceName, "producer_config.0.batch_builder", "KEY_BASED"),
+				),
+			},
+			{
+				Config: testPulsarFunctionWithProducerConfig(testWebServiceURL, name, 1000),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "producer_config.0.max_pending_messages", "1000"),
+				),
+			},
+		},
+	})
+}
+
+func testPulsarFunctionWithProducerConfig(url, name string, maxPendingMessages int) string {
+	return fmt.Sprintf(`
+provider "pulsar" {
+  web_service_url = "%s"
+}
+
+resource "pulsar_function" "producer_config" {
+  name        = "%s"
+  tenant      = "public"
+  namespace   = "default"
+  parallelism = 1
+  jar         = "function://public/default/api-examples@v1"
+  classname   = "org.apache.pulsar.functions.api.examples.ExclamationFunction"
+  inputs      = ["public/default/in-%s"]
+  output      = "public/default/out-%s"
+
+  producer_config {
+    max_pending_messages = %d
+    batch_builder        = "KEY_BASED"
+  }
+}
+`, url, name, name, name, maxPendingMessages)
+}
+
+func TestFunctionWithSecret(t *testing.T) {
+	resourceName := "pulsar_function.secret"
+	name := testAccRandomName()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testPulsarFunctionDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testPulsarFunctionWithSecret(testWebServiceURL, name, "PASSWORD"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("ERROR_DUPLICATE_SECRET_NAME: PASSWORD"),
+			},
+			{
+				Config: testPulsarFunctionWithSecret(testWebServiceURL, name, "TOKEN"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "secret.#", "2"),
+					resource.TestCheckNoResourceAttr(resourceName, "secrets"),
+					testPulsarFunctionSecrets(name, map[string]interface{}{
+						"PASSWORD": map[string]interface{}{"path": "db-credentials", "key": "password"},
+						"TOKEN":    map[string]interface{}{"path": "db-credentials", "key": "token"},
+					}),
+				),
+			},
+		},
+	})
+}
+
+func testPulsarFunctionSecrets(name string, expected map[string]interface{}) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		config, err := getPulsarFunctionByResourceID("public/default/" + name)
+		if err != nil {
+			return err
+		}
+		if fmt.Sprint(config.Secrets) != fmt.Sprint(expected) {
+			return fmt.Errorf("ERROR_UNEXPECTED_SECRETS: expected %v, got %v", expected, config.Secrets)
+		}
+		return nil
+	}
+}
+
+func testPulsarFunctionWithSecret(url, name, secondSecret string) string {
+	return fmt.Sprintf(`
+provider "pulsar" {
+  web_service_url = "%s"
+}
+
+resource "pulsar_function" "secret" {
+  name        = "%s"
+  tenant      = "public"
+  namespace   = "default"
+  parallelism = 1
+  jar         = "function://public/default/api-examples@v1"
+  classname   = "org.apache.pulsar.functions.api.examples.ExclamationFunction"
+  inputs      = ["public/default/in-%s"]
+  output      = "public/default/out-%s"
+
+  secret {
+    name = "PASSWORD"
+    path = "db-credentials"
+    key  = "password"
+  }
+
+  secret {
+    name = "%s"
+    path = "db-credentials"
+    key  = "token"
+  }
+}
+`, url, name, name, name, secondSecret)
+}
+
+func TestFunctionUnit(t *testing.T) {
+	fake := newFakeAdminServer(t)
+	resourceName := "pulsar_function.test"
+	functionPath := "/admin/v3/functions/public/default/echo"
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testUnitPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: fake.checkDestroyed("pulsar_function", func(id string) string {
+			return "/admin/v3/functions/" + id
+		}),
+		Steps: []resource.TestStep{
+			{
+				PreConfig:   func() { fake.injectError(http.MethodPost, functionPath, http.StatusInternalServerError, 1) },
+				Config:      testPulsarFunctionUnit(fake.URL, 1, DesiredStateRunning),
+				ExpectError: regexp.MustCompile("ERROR_CREATE_FUNCTION"),
+			},
+			{
+				Config: testPulsarFunctionUnit(fake.URL, 1, DesiredStateRunning),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "secret.#", "1"),
+					testFakeFunctionNumRunning(fake, 1),
+				),
+			},
+			{
+				Config: testPulsarFunctionUnit(fake.URL, 2, DesiredStateStopped),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "parallelism", "2"),
+					resource.TestCheckResourceAttr(resourceName, "desired_state", DesiredStateStopped),
+					testFakeFunctionNumRunning(fake, 0),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateId:           "public/default/echo",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"wait_for_running"},
+			},
+			{
+				PreConfig: func() {
+					var config map[string]interface{}
+					fake.get(functionPath, &config)
+					config["parallelism"] = 3
+					fake.put(functionPath, config)
+				},
+				Config:             testPulsarFunctionUnit(fake.URL, 2, DesiredStateStopped),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testFakeFunctionNumRunning(fake *fakeAdminServer, numRunning int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client, err := sharedClientWithVersion(fake.URL, config.V3)
+		if err != nil {
+			return err
+		}
+
+		status, err := client.Functions().GetFunctionStatus("public", "default", "echo")
+		if err != nil {
+			return fmt.Errorf("ERROR_READ_FUNCTION_STATUS: %w", err)
+		}
+		if status.NumRunning != numRunning {
+			return fmt.Errorf("expected %d running instances, got %d", numRunning, status.NumRunning)
+		}
+		return nil
+	}
+}
+
+func testPulsarFunctionUnit(url string, parallelism int, desiredState string) string {
+	return testFakeProvider(url) + fmt.Sprintf(`
+resource "pulsar_function" "test" {
+  name          = "echo"
+  tenant        = "public"
+  namespace     = "default"
+  parallelism   = %d
+  desired_state = "%s"
+  jar           = "function://public/default/api-examples@v1"
+  classname     = "org.apache.pulsar.functions.api.examples.ExclamationFunction"
+  inputs        = ["public/default/echo-in"]
+  output        = "public/default/echo-out"
+
+  secret {
+    name = "PASSWORD"
+    path = "db-credentials"
+    key  = "password"
+  }
+}
+`, parallelism, desiredState)
+}
+
+func TestFunctionWaitForRunningUnit(t *testing.T) {
+	fake := newFakeAdminServer(t)
+	functionPath := "/admin/v3/functions/public/default/echo"
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testUnitPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: fake.checkDestroyed("pulsar_function", func(id string) string {
+			return "/admin/v3/functions/" + id
+		}),
+		Steps: []resource.TestStep{
+			{
+				PreConfig:   func() { fake.setInstanceError(functionPath, "Function not scheduled") },
+				Config:      testPulsarFunctionWaitForRunningUnit(fake.URL),
+				ExpectError: regexp.MustCompile("ERROR_WAIT_FOR_RUNNING"),
+			},
+			{
+				// the function is tainted by the failed wait and replaced, it is not created twice
+				PreConfig: func() { fake.setInstanceError(functionPath, "") },
+				Config:    testPulsarFunctionWaitForRunningUnit(fake.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("pulsar_function.test", "id", "public/default/echo"),
+					testFakeFunctionNumRunning(fake, 1),
+				),
+			},
+		},
+	})
+}
+
+func testPulsarFunctionWaitForRunningUnit(url string) string {
+	return testFakeProvider(url) + `
+resource "pulsar_function" "test" {
+  name             = "echo"
+  tenant           = "public"
+  namespace        = "default"
+  parallelism      = 1
+  wait_for_running = true
+  jar              = "function://public/default/api-examples@v1"
+  classname        = "org.apache.pulsar.functions.api.examples.ExclamationFunction"
+  inputs           = ["public/default/echo-in"]
+  output           = "public/default/echo-out"
+
+  timeouts {
+    create = "2s"
+  }
+}
+`
+}
+
+func TestFunctionDesiredStateUnit(t *testing.T) {
+	fake := newFakeAdminServer(t)
+	resourceName := "pulsar_function.test"
+	functionPath := "/admin/v3/functions/public/default/echo"
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testUnitPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: fake.checkDestroyed("pulsar_function", func(id string) string {
+			return "/admin/v3/functions/" + id
+		}),
+		Steps: []resource.TestStep{
+			{
+				// the instances are scheduled but not running yet
+				PreConfig: func() { fake.setInstanceError(functionPath, "Function not scheduled") },
+				Config:    testPulsarFunctionUnit(fake.URL, 1, DesiredStateRunning),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "desired_state", DesiredStateRunning),
+					testFakeFunctionNumRunning(fake, 0),
+				),
+			},
+			{
+				Config:   testPulsarFunctionUnit(fake.URL, 1, DesiredStateRunning),
+				PlanOnly: true,
+			},
+			{
+				PreConfig: func() { fake.setInstanceError(functionPath, "") },
+				Config:    testPulsarFunctionUnit(fake.URL, 1, DesiredStateRunning),
+				Check:     testFakeFunctionNumRunning(fake, 1),
+			},
+			{
+				// the instances stopped out of band are reported as a drift
+				PreConfig: func() {
+					client, err := sharedClientWithVersion(fake.URL, config.V3)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if err := client.Functions().StopFunction("public", "default", "echo"); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config:             testPulsarFunctionUnit(fake.URL, 1, DesiredStateRunning),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testPulsarFunctionUnit(fake.URL, 1, DesiredStateStopped),
+				Check:  resource.TestCheckResourceAttr(resourceName, "desired_state", DesiredStateStopped),
+			},
+		},
+	})
+}
+
+func TestFunctionValidationUnit(t *testing.T) {
+	fake := newFakeAdminServer(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testUnitPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testPulsarFunctionValidation(fake.URL, `inputs = ["public/default/echo-in"]`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("ERROR_INVALID_ARCHIVE"),
+			},
+			{
+				Config: testPulsarFunctionValidation(fake.URL, `
+  jar    = "function://public/default/api-examples@v1"
+  py     = "function://public/default/api-examples-py@v1"
+  inputs = ["public/default/echo-in"]`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("ERROR_INVALID_ARCHIVE"),
+			},
+			{
+				Config:      testPulsarFunctionValidation(fake.URL, `jar = "function://public/default/api-examples@v1"`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("ERROR_MISSING_INPUTS"),
+			},
+			{
+				Config: testPulsarFunctionValidation(fake.URL, `
+  jar    = "function://public/default/api-examples@v1"
+  inputs = ["echo-in"]`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("ERROR_INVALID_TOPIC_NAME: inputs"),
+			},
+			{
+				Config: testPulsarFunctionValidation(fake.URL, `
+  jar                   = "function://public/default/api-examples@v1"
+  inputs                = ["public/default/echo-in"]
+  processing_guarantees = "EXACTLY_ONCE"`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("expected processing_guarantees to be one of"),
+			},
+			{
+				Config: testPulsarFunctionValidation(fake.URL, `
+  jar                   = "function://public/default/api-examples@v1"
+  inputs                = ["public/default/echo-in"]
+  processing_guarantees = "EFFECTIVELY_ONCE"
+  max_message_retries   = 3`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("ERROR_INCOMPATIBLE_OPTIONS: max_message_retries"),
+			},
+			{
+				Config: testPulsarFunctionValidation(fake.URL, `
+  jar                   = "function://public/default/api-examples@v1"
+  inputs                = ["public/default/echo-in"]
+  processing_guarantees = "ATMOST_ONCE"
+  timeout_ms            = 1000`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("ERROR_INCOMPATIBLE_OPTIONS: timeout_ms"),
+			},
+			{
+				Config: testPulsarFunctionValidation(fake.URL, `
+  jar                 = "function://public/default/api-examples@v1"
+  inputs              = ["public/default/echo-in"]
+  retain_ordering     = true
+  retain_key_ordering = true`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("ERROR_INCOMPATIBLE_OPTIONS: retain_ordering"),
+			},
+			{
+				Config: testPulsarFunctionValidation(fake.URL, `
+  jar               = "function://public/default/api-examples@v1"
+  inputs            = ["public/default/echo-in"]
+  dead_letter_topic = "public/default/echo-dlq"`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("ERROR_INCOMPATIBLE_OPTIONS: dead_letter_topic"),
+			},
+			{
+				Config: testPulsarFunctionValidation(fake.URL, `
+  jar    = "function://public/default/api-examples@v1"
+  inputs = ["public/default/echo-in"]
+
+  window_config {
+    sliding_interval_count = 5
+  }`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("ERROR_INVALID_WINDOW_CONFIG: either window_length_count"),
+			},
+			{
+				Config: testPulsarFunctionValidation(fake.URL, `
+  jar    = "function://public/default/api-examples@v1"
+  inputs = ["public/default/echo-in"]
+
+  window_config {
+    window_length_count          = 10
+    sliding_interval_count       = 5
+    sliding_interval_duration_ms = 1000
+  }`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("ERROR_INVALID_WINDOW_CONFIG: sliding_interval_count"),
+			},
+			{
+				Config: testPulsarFunctionValidation(fake.URL, `
+  jar    = "function://public/default/api-examples@v1"
+  inputs = ["public/default/echo-in"]
+
+  window_config {
+    window_length_count = 10
+    max_lag_ms          = 0
+  }`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("ERROR_INVALID_WINDOW_CONFIG: max_lag_ms requires"),
+			},
+			{
+				// regex patterns are left to the worker
+				Config: testPulsarFunctionValidation(fake.URL, `
+  jar = "function://public/default/api-examples@v1"
+
+  input_specs {
+    key              = "public/default/echo-.*"
+    is_regex_pattern = true
+  }`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestFunctionInputSpecsUnit(t *testing.T) {
+	fake := newFakeAdminServer(t)
+	resourceName := "pulsar_function.test"
+	functionPath := "/admin/v3/functions/public/default/echo"
+	inputs := `
+  jar    = "function://public/default/api-examples@v1"
+  inputs = ["persistent://public/default/echo-in"]`
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testUnitPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: fake.checkDestroyed("pulsar_function", func(id string) string {
+			return "/admin/v3/functions/" + id
+		}),
+		Steps: []resource.TestStep{
+			{
+				Config: testPulsarFunctionValidation(fake.URL, inputs+`
+
+  input_specs {
+    key                 = "persistent://public/default/echo-in"
+    receiver_queue_size = 100
+  }`),
+				Check: resource.TestCheckResourceAttr(resourceName, "input_specs.#", "1"),
+			},
+			{
+				// removing the input specs is planned
+				Config:             testPulsarFunctionValidation(fake.URL, inputs),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testPulsarFunctionValidation(fake.URL, inputs),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "input_specs.#", "0"),
+					// the worker merges the input specs, so the removed spec is reset
+					func(*terraform.State) error {
+						var config utils.FunctionConfig
+						fake.get(functionPath, &config)
+						spec, ok := config.InputSpecs["persistent://public/default/echo-in"]
+						if !ok || spec.ReceiverQueueSize != 0 {
+							return fmt.Errorf("expected the input spec to be reset, got %+v", config.InputSpecs)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				// the worker holds a spec for each of the inputs, they are not read back unless configured
+				PreConfig: func() {
+					var config map[string]interface{}
+					fake.get(functionPath, &config)
+					config["inputSpecs"] = map[string]interface{}{
+						"persistent://public/default/echo-in": map[string]interface{}{"receiverQueueSize": 1000},
+					}
+					fake.put(functionPath, config)
+				},
+				Config:             testPulsarFunctionValidation(fake.URL, inputs),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: false,
+			},
+		},
+	})
+}
+
+func TestFunctionCustomSchemaInputUnit(t *testing.T) {
+	fake := newFakeAdminServer(t)
+	resourceName := "pulsar_function.test"
+	functionPath := "/admin/v3/functions/public/default/echo"
+	inputs := `
+  jar    = "function://public/default/api-examples@v1"
+  inputs = ["persistent://public/default/echo-in"]`
+	customSchemaInput := inputs + `
+
+  custom_schema_input {
+    key               = "persistent://public/default/echo-in"
+    schema_type       = "JSON"
+    schema_properties = {
+      "__jsr310ConversionEnabled" = "true"
+    }
+  }`
+	testWorkerCustomSchemaInput := func(expected string) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			var config utils.FunctionConfig
+			fake.get(functionPath, &config)
+			if value := config.CustomSchemaInputs["persistent://public/default/echo-in"]; value != expected {
+				return fmt.Errorf("expected the custom schema input %q, got %q", expected, value)
+			}
+			return nil
+		}
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testUnitPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: fake.checkDestroyed("pulsar_function", func(id string) string {
+			return "/admin/v3/functions/" + id
+		}),
+		Steps: []resource.TestStep{
+			{
+				Config: testPulsarFunctionValidation(fake.URL, customSchemaInput),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "custom_schema_input.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "custom_schema_input.0.schema_type", "JSON"),
+					testWorkerCustomSchemaInput(`{"schemaType":"JSON","schemaProperties":{"__jsr310ConversionEnabled":"true"}}`),
+				),
+			},
+			{
+				Config:   testPulsarFunctionValidation(fake.URL, customSchemaInput),
+				PlanOnly: true,
+			},
+			{
+				// the worker merges the custom schema inputs, so the removed one is reset to the default schema
+				Config: testPulsarFunctionValidation(fake.URL, inputs),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "custom_schema_input.#", "0"),
+					testWorkerCustomSchemaInput(""),
+				),
+			},
+			{
+				Config:   testPulsarFunctionValidation(fake.URL, inputs),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestFunctionProducerConfigUnit(t *testing.T) {
+	fake := newFakeAdminServer(t)
+	resourceName := "pulsar_function.test"
+	functionPath := "/admin/v3/functions/public/default/echo"
+	inputs := `
+  jar    = "function://public/default/api-examples@v1"
+  inputs = ["persistent://public/default/echo-in"]`
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testUnitPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: fake.checkDestroyed("pulsar_function", func(id string) string {
+			return "/admin/v3/functions/" + id
+		}),
+		Steps: []resource.TestStep{
+			{
+				Config: testPulsarFunctionValidation(fake.URL, inputs+`
+
+  producer_config {
+    max_pending_messages = 500
+    batch_builder        = "KEY_BASED"
+  }`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "producer_config.0.max_pending_messages", "500"),
+					// the worker fills in its default compression type
+					resource.TestCheckResourceAttr(resourceName, "producer_config.0.compression_type", "LZ4"),
+				),
+			},
+			{
+				// removing the producer config is planned
+				Config:             testPulsarFunctionValidation(fake.URL, inputs),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testPulsarFunctionValidation(fake.URL, inputs),
+				Check: resource.ComposeTestCheckFunc(
+					func(s *terraform.State) error {
+						attributes := s.RootModule().Resources[resourceName].Primary.Attributes
+						if v := attributes["producer_config.0.max_pending_messages"]; v != "" && v != "0" {
+							return fmt.Errorf("expected max_pending_messages to be cleared, got %s", v)
+						}
+						if v := attributes["producer_config.0.batch_builder"]; v != "" {
+							return fmt.Errorf("expected batch_builder to be cleared, got %s", v)
+						}
+						return nil
+					},
+					// the worker keeps the producer config when none is sent, so it is reset
+					func(*terraform.State) error {
+						var config utils.FunctionConfig
+						fake.get(functionPath, &config)
+						if config.ProducerConfig == nil || config.ProducerConfig.MaxPendingMessages != 0 ||
+							config.ProducerConfig.BatchBuilder != "" {
+							return fmt.Errorf("expected the producer config to be cleared, got %+v", config.ProducerConfig)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				// the defaults reported by the worker are not planned
+				Config:             testPulsarFunctionValidation(fake.URL, inputs),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: false,
+			},
+		},
+	})
+}
+
+func testPulsarFunctionValidation(url, attributes string) string {
+	return testFakeProvider(url) + fmt.Sprintf(`
+resource "pulsar_function" "test" {
+  name      = "echo"
+  tenant    = "public"
+  namespace = "default"
+  classname = "org.apache.pulsar.functions.api.examples.ExclamationFunction"
+  %s
+}
+`, attributes)
+}
+
+func TestFunctionUnknownArchiveSHA256(t *testing.T) {
+	r := resourcePulsarFunction()
+	raw, err := r.CoreConfigSchema().CoerceValue(cty.ObjectVal(map[string]cty.Value{
+		"tenant":    cty.StringVal("public"),
+		"namespace": cty.StringVal("default"),
+		"name":      cty.StringVal("echo"),
+		"inputs":    cty.SetVal([]cty.Value{cty.StringVal("persistent://public/default/echo-in")}),
+		// the path of an archive built by another resource is only known during the apply
+		"jar": cty.UnknownVal(cty.String),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := &terraform.InstanceState{
+		ID: "public/default/echo",
+		Attributes: map[string]string{
+			"tenant":         "public",
+			"namespace":      "default",
+			"name":           "echo",
+			"inputs.#":       "1",
+			"inputs.0":       "persistent://public/default/echo-in",
+			"jar":            "build/echo.jar",
+			"archive_sha256": "4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b",
+		},
+		RawConfig: raw,
+	}
+
+	diff, err := r.SimpleDiff(context.Background(), state, terraform.NewResourceConfigShimmed(raw, r.CoreConfigSchema()),
+		nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attr := diff.Attributes["archive_sha256"]; attr == nil || !attr.NewComputed {
+		t.Fatalf("expected archive_sha256 to be computed, got %+v", attr)
+	}
+}
+
+func TestFunctionWindowConfigZeroMaxLag(t *testing.T) {
+	r := resourcePulsarFunction()
+	raw, err := r.CoreConfigSchema().CoerceValue(cty.ObjectVal(map[string]cty.Value{
+		"tenant":    cty.StringVal("public"),
+		"namespace": cty.StringVal("default"),
+		"name":      cty.StringVal("echo"),
+		"window_config": cty.ListVal([]cty.Value{cty.ObjectVal(map[string]cty.Value{
+			"window_length_duration_ms":     cty.NumberIntVal(60000),
+			"timestamp_extractor_classname": cty.StringVal("org.example.TimestampExtractor"),
+			"max_lag_ms":                    cty.NumberIntVal(0),
+		})}),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	windowConfig := marshalWindowConfig([]interface{}{map[string]interface{}{
+		resourceFunctionWindowLengthCountKey:           0,
+		resourceFunctionWindowLengthDurationKey:        60000,
+		resourceFunctionSlidingIntervalCountKey:        0,
+		resourceFunctionSlidingIntervalDurationKey:     0,
+		resourceFunctionLateDataTopicKey:               "",
+		resourceFunctionMaxLagKey:                      0,
+		resourceFunctionWatermarkEmitIntervalKey:       0,
+		resourceFunctionTimestampExtractorClassNameKey: "org.example.TimestampExtractor",
+	}}, raw)
+
+	if windowConfig.MaxLagMs == nil || *windowConfig.MaxLagMs != 0 {
+		t.Fatalf("expected the configured max_lag_ms of 0 to be sent, got %v", windowConfig.MaxLagMs)
+	}
+	if windowConfig.WindowLengthCount != nil || windowConfig.WatermarkEmitIntervalMs != nil {
+		t.Fatalf("expected only the configured attributes to be sent, got %+v", windowConfig)
+	}
+}
@@ -21,10 +21,12 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/utils"
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -34,7 +36,7 @@ import (
 )
 
 func resourcePulsarNamespace() *schema.Resource {
-	return &schema.Resource{
+	r := &schema.Resource{
 		CreateContext: resourcePulsarNamespaceCreate,
 		ReadContext:   resourcePulsarNamespaceRead,
 		UpdateContext: resourcePulsarNamespaceUpdate,
@@ -56,6 +58,8 @@ func resourcePulsarNamespace() *schema.Resource {
 				return []*schema.ResourceData{d}, nil
 			},
 		},
+		// namespace_config used to be a set, replaced as a whole on any change, with -1 for the unset limits
+		SchemaVersion: 1,
 		Schema: map[string]*schema.Schema{
 			"namespace": {
 				Type:        schema.TypeString,
@@ -142,7 +146,7 @@ func resourcePulsarNamespace() *schema.Resource {
 				Set:      hashBacklogQuotaSubset(),
 			},
 			"namespace_config": {
-				Type:        schema.TypeSet,
+				Type:        schema.TypeList,
 				Optional:    true,
 				Description: descriptions["namespace_config"],
 				MaxItems:    1,
@@ -156,30 +160,27 @@ func resourcePulsarNamespace() *schema.Resource {
 						"max_consumers_per_subscription": {
 							Type:         schema.TypeInt,
 							Optional:     true,
-							Default:      -1,
 							ValidateFunc: validateGtEq0,
 						},
 						"max_consumers_per_topic": {
 							Type:         schema.TypeInt,
 							Optional:     true,
-							Default:      -1,
 							ValidateFunc: validateGtEq0,
 						},
 						"max_producers_per_topic": {
 							Type:         schema.TypeInt,
 							Optional:     true,
-							Default:      -1,
 							ValidateFunc: validateGtEq0,
 						},
 						"message_ttl_seconds": {
 							Type:         schema.TypeInt,
 							Optional:     true,
-							Default:      -1,
 							ValidateFunc: validateGtEq0,
 						},
 						"replication_clusters": {
 							Type:     schema.TypeList,
 							Optional: true,
+							Computed: true,
 							MinItems: 1,
 							Elem: &schema.Schema{
 								Type: schema.TypeString,
@@ -204,12 +205,11 @@ func resourcePulsarNamespace() *schema.Resource {
 						"offload_threshold_size_in_mb": {
 							Type:         schema.TypeInt,
 							Optional:     true,
-							Default:      -1,
+							Computed:     true,
 							ValidateFunc: validateGtEq0,
 						},
 					},
 				},
-				Set: namespaceConfigToHash,
 			},
 			"persistence_policies": {
 				Type:     schema.TypeSet,
@@ -237,6 +237,7 @@ func resourcePulsarNamespace() *schema.Resource {
 				},
 				Set: persistencePoliciesToHash,
 			},
+			"permission_grant_mode": schemaPermissionGrantMode(),
 			"permission_grant": {
 				Type:     schema.TypeSet,
 				Optional: true,
@@ -259,6 +260,29 @@ func resourcePulsarNamespace() *schema.Resource {
 					},
 				},
 			},
+			"subscription_permission": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: descriptions["subscription_permission"],
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"subscription": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateNotBlank,
+						},
+						"roles": {
+							Type:     schema.TypeSet,
+							Required: true,
+							MinItems: 1,
+							Elem: &schema.Schema{
+								Type:         schema.TypeString,
+								ValidateFunc: validateNotBlank,
+							},
+						},
+					},
+				},
+			},
 			"topic_auto_creation": {
 				Type:     schema.TypeSet,
 				Optional: true,
@@ -283,8 +307,70 @@ func resourcePulsarNamespace() *schema.Resource {
 				},
 				Set: topicAutoCreationPoliciesToHash,
 			},
+			"offload_policies": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: descriptions["offload_policies"],
+				MaxItems:    1,
+				Elem:        schemaOffloadPolicies(),
+			},
+		},
+	}
+
+	r.StateUpgraders = []schema.StateUpgrader{
+		{
+			Version: 0,
+			Type:    resourcePulsarNamespaceV0(r.Schema).CoreConfigSchema().ImpliedType(),
+			Upgrade: resourcePulsarNamespaceStateUpgradeV0,
 		},
 	}
+
+	return r
+}
+
+// resourcePulsarNamespaceV0 is the schema before namespace_config became a list, only its types matter
+func resourcePulsarNamespaceV0(current map[string]*schema.Schema) *schema.Resource {
+	v0 := make(map[string]*schema.Schema, len(current))
+	for k, v := range current {
+		v0[k] = v
+	}
+	namespaceConfig := *v0["namespace_config"]
+	namespaceConfig.Type = schema.TypeSet
+	v0["namespace_config"] = &namespaceConfig
+	return &schema.Resource{Schema: v0}
+}
+
+// namespaceConfigLimits are the limits of the namespace_config, they were -1 when not configured
+var namespaceConfigLimits = []string{
+	"max_consumers_per_subscription",
+	"max_consumers_per_topic",
+	"max_producers_per_topic",
+	"message_ttl_seconds",
+	"offload_threshold_size_in_mb",
+}
+
+// resourcePulsarNamespaceStateUpgradeV0 drops the -1 limits of the namespace_config, the next refresh
+// reads them from the brokers. The set is stored as an array like the list.
+func resourcePulsarNamespaceStateUpgradeV0(ctx context.Context, rawState map[string]interface{},
+	meta interface{}) (map[string]interface{}, error) {
+	namespaceConfig, ok := rawState["namespace_config"].([]interface{})
+	if !ok {
+		return rawState, nil
+	}
+
+	for _, v := range namespaceConfig {
+		data, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, key := range namespaceConfigLimits {
+			if value, ok := data[key]; ok && fmt.Sprint(value) == "-1" {
+				data[key] = nil
+			}
+		}
+	}
+
+	return rawState, nil
 }
 
 func resourcePulsarNamespaceCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -325,7 +411,7 @@ func resourcePulsarNamespaceRead(ctx context.Context, d *schema.ResourceData, me
 	_ = d.Set("namespace", namespace)
 	_ = d.Set("tenant", tenant)
 
-	if namespaceConfig, ok := d.GetOk("namespace_config"); ok && namespaceConfig.(*schema.Set).Len() > 0 {
+	if namespaceConfig, ok := d.GetOk("namespace_config"); ok && len(namespaceConfig.([]interface{})) > 0 {
 		afgrp, err := client.GetNamespaceAntiAffinityGroup(ns.String())
 		if err != nil {
 			return diag.FromErr(fmt.Errorf("ERROR_READ_NAMESPACE: GetNamespaceAntiAffinityGroup: %w", err))
@@ -381,7 +467,7 @@ func resourcePulsarNamespaceRead(ctx context.Context, d *schema.ResourceData, me
 			return diag.FromErr(fmt.Errorf("ERROR_READ_NAMESPACE: GetOffloadThreshold: %w", err))
 		}
 
-		_ = d.Set("namespace_config", schema.NewSet(namespaceConfigToHash, []interface{}{
+		_ = d.Set("namespace_config", []interface{}{
 			map[string]interface{}{
 				"anti_affinity":                  strings.Trim(strings.TrimSpace(afgrp), "\""),
 				"max_consumers_per_subscription": maxConsPerSub,
@@ -394,7 +480,7 @@ func resourcePulsarNamespaceRead(ctx context.Context, d *schema.ResourceData, me
 				"is_allow_auto_update_schema":    isAllowAutoUpdateSchema,
 				"offload_threshold_size_in_mb":   int(offloadTresholdSizeInMb),
 			},
-		}))
+		})
 	}
 
 	if persPoliciesCfg, ok := d.GetOk("persistence_policies"); ok && persPoliciesCfg.(*schema.Set).Len() > 0 {
@@ -476,7 +562,8 @@ func resourcePulsarNamespaceRead(ctx context.Context, d *schema.ResourceData, me
 		}))
 	}
 
-	if permissionGrantCfg, ok := d.GetOk("permission_grant"); ok && len(permissionGrantCfg.(*schema.Set).List()) > 0 {
+	permissionGrantCfg, ok := d.GetOk("permission_grant")
+	if isPermissionGrantAuthoritative(d) || (ok && len(permissionGrantCfg.(*schema.Set).List()) > 0) {
 		grants, err := client.GetNamespacePermissions(*ns)
 		if err != nil {
 			return diag.FromErr(fmt.Errorf("ERROR_READ_NAMESPACE: GetNamespacePermissions: %w", err))
@@ -485,6 +572,16 @@ func resourcePulsarNamespaceRead(ctx context.Context, d *schema.ResourceData, me
 		setPermissionGrant(d, grants)
 	}
 
+	subPermissionCfg, ok := d.GetOk("subscription_permission")
+	if isPermissionGrantAuthoritative(d) || (ok && subPermissionCfg.(*schema.Set).Len() > 0) {
+		policies, err := client.GetPolicies(ns.String())
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("ERROR_READ_NAMESPACE: GetPolicies: %w", err))
+		}
+
+		setSubscriptionPermissions(d, policies.AuthPolicies.SubscriptionAuthRoles)
+	}
+
 	if topicAutoCreation, ok := d.GetOk("topic_auto_creation"); ok && topicAutoCreation.(*schema.Set).Len() > 0 {
 		autoCreation, err := client.GetTopicAutoCreation(*ns)
 		if err != nil {
@@ -502,6 +599,14 @@ func resourcePulsarNamespaceRead(ctx context.Context, d *schema.ResourceData, me
 		_ = d.Set("topic_auto_creation", schema.NewSet(topicAutoCreationPoliciesToHash, []interface{}{data}))
 	}
 
+	var offloadPolicies types.OffloadPolicies
+	err = getRestClientFromMeta(meta).Get(namespaceRestEndpoint(ns, "offloadPolicies"), &offloadPolicies)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("ERROR_READ_NAMESPACE: GetOffloadPolicies: %w", err))
+	}
+	_ = d.Set("offload_policies", flattenOffloadPolicies(&offloadPolicies,
+		d.Get("offload_policies").([]interface{})))
+
 	return nil
 }
 
@@ -511,7 +616,7 @@ func resourcePulsarNamespaceUpdate(ctx context.Context, d *schema.ResourceData,
 	namespace := d.Get("namespace").(string)
 	tenant := d.Get("tenant").(string)
 	enableDeduplication, deduplicationDefined := d.GetOk("enable_deduplication")
-	namespaceConfig := d.Get("namespace_config").(*schema.Set)
+	namespaceConfig := d.Get("namespace_config").([]interface{})
 	retentionPoliciesConfig := d.Get("retention_policies").(*schema.Set)
 	backlogQuotaConfig := d.Get("backlog_quota").(*schema.Set)
 	dispatchRateConfig := d.Get("dispatch_rate").(*schema.Set)
@@ -519,6 +624,7 @@ func resourcePulsarNamespaceUpdate(ctx context.Context, d *schema.ResourceData,
 	persistencePoliciesConfig := d.Get("persistence_policies").(*schema.Set)
 	permissionGrantConfig := d.Get("permission_grant").(*schema.Set)
 	topicAutoCreation := d.Get("topic_auto_creation").(*schema.Set)
+	offloadPoliciesConfig := d.Get("offload_policies").([]interface{})
 
 	nsName, err := utils.GetNameSpaceName(tenant, namespace)
 	if err != nil {
@@ -527,8 +633,12 @@ func resourcePulsarNamespaceUpdate(ctx context.Context, d *schema.ResourceData,
 
 	var errs error
 
-	if namespaceConfig.Len() > 0 {
-		nsCfg := unmarshalNamespaceConfig(namespaceConfig)
+	if err = removeNamespaceConfigLimits(d, nsName, meta); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+
+	if len(namespaceConfig) > 0 {
+		nsCfg := unmarshalNamespaceConfig(namespaceConfig, d.GetRawConfig())
 
 		if len(nsCfg.AntiAffinity) > 0 {
 			if err = client.SetNamespaceAntiAffinityGroup(nsName.String(), nsCfg.AntiAffinity); err != nil {
@@ -542,32 +652,32 @@ func resourcePulsarNamespaceUpdate(ctx context.Context, d *schema.ResourceData,
 			}
 		}
 
-		if nsCfg.MaxConsumersPerTopic >= 0 {
-			if err = client.SetMaxConsumersPerTopic(*nsName, nsCfg.MaxConsumersPerTopic); err != nil {
+		if nsCfg.MaxConsumersPerTopic != nil {
+			if err = client.SetMaxConsumersPerTopic(*nsName, *nsCfg.MaxConsumersPerTopic); err != nil {
 				errs = multierror.Append(errs, fmt.Errorf("SetMaxConsumersPerTopic: %w", err))
 			}
 		}
 
-		if nsCfg.MaxConsumersPerSubscription >= 0 {
-			if err = client.SetMaxConsumersPerSubscription(*nsName, nsCfg.MaxConsumersPerSubscription); err != nil {
+		if nsCfg.MaxConsumersPerSubscription != nil {
+			if err = client.SetMaxConsumersPerSubscription(*nsName, *nsCfg.MaxConsumersPerSubscription); err != nil {
 				errs = multierror.Append(errs, fmt.Errorf("SetMaxConsumersPerSubscription: %w", err))
 			}
 		}
 
-		if nsCfg.MaxProducersPerTopic >= 0 {
-			if err = client.SetMaxProducersPerTopic(*nsName, nsCfg.MaxProducersPerTopic); err != nil {
+		if nsCfg.MaxProducersPerTopic != nil {
+			if err = client.SetMaxProducersPerTopic(*nsName, *nsCfg.MaxProducersPerTopic); err != nil {
 				errs = multierror.Append(errs, fmt.Errorf("SetMaxProducersPerTopic: %w", err))
 			}
 		}
 
-		if nsCfg.MessageTTLInSeconds >= 0 {
-			if err = client.SetNamespaceMessageTTL(nsName.String(), nsCfg.MessageTTLInSeconds); err != nil {
+		if nsCfg.MessageTTLInSeconds != nil {
+			if err = client.SetNamespaceMessageTTL(nsName.String(), *nsCfg.MessageTTLInSeconds); err != nil {
 				errs = multierror.Append(errs, fmt.Errorf("SetNamespaceMessageTTL: %w", err))
 			}
 		}
 
-		if nsCfg.OffloadThresholdSizeInMb >= 0 {
-			if err = client.SetOffloadThreshold(*nsName, int64(nsCfg.OffloadThresholdSizeInMb)); err != nil {
+		if nsCfg.OffloadThresholdSizeInMb != nil {
+			if err = client.SetOffloadThreshold(*nsName, int64(*nsCfg.OffloadThresholdSizeInMb)); err != nil {
 				errs = multierror.Append(errs, fmt.Errorf("SetOffloadThreshold: %w", err))
 			}
 		}
@@ -637,32 +747,53 @@ func resourcePulsarNamespaceUpdate(ctx context.Context, d *schema.ResourceData,
 		}
 	}
 
-	if d.HasChange("permission_grant") {
+	if d.HasChange("permission_grant") || d.HasChange("permission_grant_mode") {
 		permissionGrants, err := unmarshalPermissionGrants(permissionGrantConfig)
 		if err != nil {
 			errs = multierror.Append(errs, fmt.Errorf("unmarshalPermissionGrants: %w", err))
+		} else if currentGrants, err := client.GetNamespacePermissions(*nsName); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("GetNamespacePermissions: %w", err))
 		} else {
-			for _, grant := range permissionGrants {
+			oldPermissionGrants, _ := d.GetChange("permission_grant")
+			revokes, grants := diffPermissionGrants(currentGrants, permissionGrants,
+				permissionGrantRoles(oldPermissionGrants.(*schema.Set)), isPermissionGrantAuthoritative(d))
+
+			for _, role := range revokes {
+				if err = client.RevokeNamespacePermission(*nsName, role); err != nil {
+					errs = multierror.Append(errs, fmt.Errorf("RevokeNamespacePermission: %w", err))
+				}
+			}
+
+			for _, grant := range grants {
 				if err = client.GrantNamespacePermission(*nsName, grant.Role, grant.Actions); err != nil {
 					errs = multierror.Append(errs, fmt.Errorf("GrantNamespacePermission: %w", err))
 				}
 			}
+		}
+	}
 
-			// Revoke permissions for roles removed from the set
-			oldPermissionGrants, _ := d.GetChange("permission_grant")
-			for _, oldGrant := range oldPermissionGrants.(*schema.Set).List() {
-				oldRole := oldGrant.(map[string]interface{})["role"].(string)
-				found := false
-				for _, newGrant := range permissionGrants {
-					if newGrant.Role == oldRole {
-						found = true
-						break
-					}
-				}
-				if !found {
-					if err = client.RevokeNamespacePermission(*nsName, oldRole); err != nil {
-						errs = multierror.Append(errs, fmt.Errorf("RevokeNamespacePermission: %w", err))
-					}
+	if d.HasChange("subscription_permission") || d.HasChange("permission_grant_mode") {
+		oldSubPermissions, newSubPermissions := d.GetChange("subscription_permission")
+		oldRoles := unmarshalSubscriptionPermissions(oldSubPermissions.(*schema.Set))
+		newRoles := unmarshalSubscriptionPermissions(newSubPermissions.(*schema.Set))
+
+		// granting replaces the roles allowed on the subscription
+		for sub, roles := range newRoles {
+			if old, ok := oldRoles[sub]; ok && sameStrings(old, roles) {
+				continue
+			}
+			if err = client.GrantSubPermission(*nsName, sub, roles); err != nil {
+				errs = multierror.Append(errs, fmt.Errorf("GrantSubPermission: %w", err))
+			}
+		}
+
+		for sub, roles := range oldRoles {
+			if _, ok := newRoles[sub]; ok {
+				continue
+			}
+			for _, role := range roles {
+				if err = client.RevokeSubPermission(*nsName, sub, role); err != nil {
+					errs = multierror.Append(errs, fmt.Errorf("RevokeSubPermission: %w", err))
 				}
 			}
 		}
@@ -683,6 +814,18 @@ func resourcePulsarNamespaceUpdate(ctx context.Context, d *schema.ResourceData,
 		}
 	}
 
+	if len(offloadPoliciesConfig) > 0 {
+		offloadPolicies := unmarshalOffloadPolicies(offloadPoliciesConfig, d.GetRawConfig())
+		err = getRestClientFromMeta(meta).Post(namespaceRestEndpoint(nsName, "offloadPolicies"), offloadPolicies)
+		if err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("SetOffloadPolicies: %w", err))
+		}
+	} else if d.HasChange("offload_policies") {
+		if err = getRestClientFromMeta(meta).Delete(namespaceRestEndpoint(nsName, "removeOffloadPolicies")); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("RemoveOffloadPolicies: %w", err))
+		}
+	}
+
 	if errs != nil {
 		return diag.FromErr(fmt.Errorf("ERROR_UPDATE_NAMESPACE_CONFIG: %w", errs))
 	}
@@ -713,7 +856,9 @@ func resourcePulsarNamespaceDelete(ctx context.Context, d *schema.ResourceData,
 	_ = d.Set("subscription_dispatch_rate", nil)
 	_ = d.Set("persistence_policies", nil)
 	_ = d.Set("permission_grant", nil)
+	_ = d.Set("subscription_permission", nil)
 	_ = d.Set("topic_auto_creation", nil)
+	_ = d.Set("offload_policies", nil)
 
 	return nil
 }
@@ -739,23 +884,6 @@ func retentionPoliciesToHash(v interface{}) int {
 	return hashcode.String(buf.String())
 }
 
-func namespaceConfigToHash(v interface{}) int {
-	var buf bytes.Buffer
-	m := v.(map[string]interface{})
-
-	buf.WriteString(fmt.Sprintf("%s-", m["anti_affinity"].(string)))
-	buf.WriteString(fmt.Sprintf("%d-", m["max_consumers_per_subscription"].(int)))
-	buf.WriteString(fmt.Sprintf("%d-", m["max_consumers_per_topic"].(int)))
-	buf.WriteString(fmt.Sprintf("%d-", m["max_producers_per_topic"].(int)))
-	buf.WriteString(fmt.Sprintf("%d-", m["message_ttl_seconds"].(int)))
-	buf.WriteString(fmt.Sprintf("%s-", m["replication_clusters"].([]interface{})))
-	buf.WriteString(fmt.Sprintf("%t-", m["schema_validation_enforce"].(bool)))
-	buf.WriteString(fmt.Sprintf("%s-", m["schema_compatibility_strategy"].(string)))
-	buf.WriteString(fmt.Sprintf("%d-", m["offload_threshold_size_in_mb"].(int)))
-
-	return hashcode.String(buf.String())
-}
-
 func persistencePoliciesToHash(v interface{}) int {
 	var buf bytes.Buffer
 	m := v.(map[string]interface{})
@@ -812,28 +940,104 @@ func unmarshalRetentionPolicies(v *schema.Set) *utils.RetentionPolicies {
 	return &rtnPolicies
 }
 
-func unmarshalNamespaceConfig(v *schema.Set) *types.NamespaceConfig {
+func unmarshalNamespaceConfig(v []interface{}, rawConfig cty.Value) *types.NamespaceConfig {
 	var nsConfig types.NamespaceConfig
 
-	for _, ns := range v.List() {
-		data := ns.(map[string]interface{})
+	configured := rawConfigNamespaceConfig(rawConfig)
+	limit := func(data map[string]interface{}, key string) *int {
+		// the limits only known from the state are left to the brokers
+		if configured.IsNull() || configured.GetAttr(key).IsNull() {
+			return nil
+		}
+		value := data[key].(int)
+		return &value
+	}
+
+	for _, ns := range v {
+		data, ok := ns.(map[string]interface{})
+		if !ok {
+			continue
+		}
 		rplClusters := data["replication_clusters"].([]interface{})
 
 		nsConfig.ReplicationClusters = handleHCLArrayV2(rplClusters)
-		nsConfig.MaxProducersPerTopic = data["max_producers_per_topic"].(int)
-		nsConfig.MaxConsumersPerTopic = data["max_consumers_per_topic"].(int)
-		nsConfig.MaxConsumersPerSubscription = data["max_consumers_per_subscription"].(int)
-		nsConfig.MessageTTLInSeconds = data["message_ttl_seconds"].(int)
+		nsConfig.MaxProducersPerTopic = limit(data, "max_producers_per_topic")
+		nsConfig.MaxConsumersPerTopic = limit(data, "max_consumers_per_topic")
+		nsConfig.MaxConsumersPerSubscription = limit(data, "max_consumers_per_subscription")
+		nsConfig.MessageTTLInSeconds = limit(data, "message_ttl_seconds")
 		nsConfig.AntiAffinity = data["anti_affinity"].(string)
 		nsConfig.SchemaValidationEnforce = data["schema_validation_enforce"].(bool)
 		nsConfig.SchemaCompatibilityStrategy = data["schema_compatibility_strategy"].(string)
 		nsConfig.IsAllowAutoUpdateSchema = data["is_allow_auto_update_schema"].(bool)
-		nsConfig.OffloadThresholdSizeInMb = data["offload_threshold_size_in_mb"].(int)
+		nsConfig.OffloadThresholdSizeInMb = limit(data, "offload_threshold_size_in_mb")
 	}
 
 	return &nsConfig
 }
 
+// namespaceConfigRemovals are the endpoints removing the limits of the namespace_config from a namespace
+var namespaceConfigRemovals = []struct {
+	key      string
+	endpoint string
+}{
+	{"max_consumers_per_subscription", "maxConsumersPerSubscription"},
+	{"max_consumers_per_topic", "maxConsumersPerTopic"},
+	{"max_producers_per_topic", "maxProducersPerTopic"},
+	{"message_ttl_seconds", "messageTTL"},
+}
+
+// removeNamespaceConfigLimits removes the limits which are not configured anymore, the brokers apply
+// their defaults to them again
+func removeNamespaceConfigLimits(d *schema.ResourceData, nsName *utils.NameSpaceName, meta interface{}) error {
+	o, _ := d.GetChange("namespace_config")
+	previous := o.([]interface{})
+	if len(previous) == 0 || previous[0] == nil {
+		return nil
+	}
+	data := previous[0].(map[string]interface{})
+
+	configured := rawConfigNamespaceConfig(d.GetRawConfig())
+	removed := func(key string) bool {
+		return configured.IsNull() || configured.GetAttr(key).IsNull()
+	}
+
+	var errs error
+	for _, removal := range namespaceConfigRemovals {
+		if data[removal.key].(int) == 0 || !removed(removal.key) {
+			continue
+		}
+		if err := getRestClientFromMeta(meta).Delete(namespaceRestEndpoint(nsName, removal.endpoint)); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("Remove %s: %w", removal.endpoint, err))
+		}
+	}
+
+	if data["anti_affinity"].(string) != "" && removed("anti_affinity") {
+		if err := getClientFromMeta(meta).Namespaces().DeleteNamespaceAntiAffinityGroup(nsName.String()); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("DeleteNamespaceAntiAffinityGroup: %w", err))
+		}
+	}
+
+	return errs
+}
+
+// rawConfigNamespaceConfig returns the configured namespace_config block, null when it is not configured
+func rawConfigNamespaceConfig(raw cty.Value) cty.Value {
+	if raw.IsNull() || !raw.IsKnown() {
+		return cty.NullVal(cty.DynamicPseudoType)
+	}
+
+	namespaceConfig := raw.GetAttr("namespace_config")
+	if namespaceConfig.IsNull() || !namespaceConfig.IsKnown() || namespaceConfig.LengthInt() == 0 {
+		return cty.NullVal(cty.DynamicPseudoType)
+	}
+
+	block := namespaceConfig.Index(cty.NumberIntVal(0))
+	if !block.IsKnown() {
+		return cty.NullVal(cty.DynamicPseudoType)
+	}
+	return block
+}
+
 func unmarshalPersistencePolicies(v *schema.Set) *utils.PersistencePolicies {
 	var persPolicies utils.PersistencePolicies
 
@@ -849,6 +1053,42 @@ func unmarshalPersistencePolicies(v *schema.Set) *utils.PersistencePolicies {
 	return &persPolicies
 }
 
+func unmarshalSubscriptionPermissions(v *schema.Set) map[string][]string {
+	subPermissions := make(map[string][]string)
+
+	for _, item := range v.List() {
+		data := item.(map[string]interface{})
+		roles := handleHCLArrayV2(data["roles"].(*schema.Set).List())
+		sort.Strings(roles)
+		subPermissions[data["subscription"].(string)] = roles
+	}
+
+	return subPermissions
+}
+
+// setSubscriptionPermissions follows the permission_grant_mode: in additive mode only the managed
+// subscriptions are kept in the state
+func setSubscriptionPermissions(d *schema.ResourceData, subAuthRoles map[string][]string) {
+	managed := unmarshalSubscriptionPermissions(d.Get("subscription_permission").(*schema.Set))
+	authoritative := isPermissionGrantAuthoritative(d)
+
+	subPermissions := []interface{}{}
+	for sub, roles := range subAuthRoles {
+		if _, ok := managed[sub]; !ok && !authoritative {
+			continue
+		}
+		if len(roles) == 0 {
+			continue
+		}
+		subPermissions = append(subPermissions, map[string]interface{}{
+			"subscription": sub,
+			"roles":        schema.NewSet(schema.HashString, stringsToInterfaces(roles)),
+		})
+	}
+
+	_ = d.Set("subscription_permission", subPermissions)
+}
+
 func unmarshalTopicAutoCreation(v *schema.Set) (*utils.TopicAutoCreationConfig, error) {
 	var topicAutoCreation utils.TopicAutoCreationConfig
 
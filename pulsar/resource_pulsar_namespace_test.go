@@ -18,12 +18,13 @@
 package pulsar
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"testing"
 
 	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/admin"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
@@ -32,17 +33,13 @@ func init() {
 	initTestWebServiceURL()
 
 	resource.AddTestSweepers("pulsar_namespace", &resource.Sweeper{
-		Name: "pulsar_namespace",
-		F:    testSweepNS,
-		Dependencies: []string{
-			"pulsar_cluster",
-			"pulsar_tenant",
-		},
+		Name:         "pulsar_namespace",
+		F:            testSweepNS,
+		Dependencies: []string{"pulsar_topic"},
 	})
 }
 
 func testSweepNS(url string) error {
-
 	client, err := sharedClient(url)
 	if err != nil {
 		return fmt.Errorf("ERROR_GETTING_PULSAR_CLIENT: %w", err)
@@ -50,31 +47,30 @@ func testSweepNS(url string) error {
 
 	conn := client.(admin.Client)
 
-	tenants, err := conn.Tenants().List()
+	namespaces, err := sweepNamespaces(url)
 	if err != nil {
-		return fmt.Errorf("ERROR_GETTING_TENANTS: %w", err)
+		return err
 	}
 
-	for _, t := range tenants {
-		nsList, err := conn.Namespaces().GetNamespaces(t)
-		if err != nil {
-			return fmt.Errorf("ERROR_GETTING_NAMESPACE_LIST: %w", err)
+	var errs error
+	for _, ns := range namespaces {
+		if !isSweepable(strings.Split(ns, "/")...) {
+			continue
 		}
-
-		for _, ns := range nsList {
-			return conn.Namespaces().DeleteNamespace(ns)
+		if err := conn.Namespaces().DeleteNamespace(ns); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("ERROR_DELETING_NAMESPACE %s: %w", ns, err))
 		}
 	}
 
-	return nil
+	return errs
 }
 
 func TestNamespace(t *testing.T) {
 
 	resourceName := "pulsar_namespace.test"
-	cName := acctest.RandString(10)
-	tName := acctest.RandString(10)
-	nsName := acctest.RandString(10)
+	cName := testAccRandomName()
+	tName := testAccRandomName()
+	nsName := testAccRandomName()
 
 	resource.Test(t, resource.TestCase{
 		PreCheck:          func() { testAccPreCheck(t) },
@@ -95,9 +91,9 @@ func TestNamespace(t *testing.T) {
 func TestNamespaceWithUpdate(t *testing.T) {
 
 	resourceName := "pulsar_namespace.test"
-	cName := acctest.RandString(10)
-	tName := acctest.RandString(10)
-	nsName := acctest.RandString(10)
+	cName := testAccRandomName()
+	tName := testAccRandomName()
+	nsName := testAccRandomName()
 
 	resource.Test(t, resource.TestCase{
 		PreCheck:          func() { testAccPreCheck(t) },
@@ -149,9 +145,9 @@ func TestNamespaceWithUpdate(t *testing.T) {
 func TestNamespaceWithUndefinedOptionalsUpdate(t *testing.T) {
 
 	resourceName := "pulsar_namespace.test"
-	cName := acctest.RandString(10)
-	tName := acctest.RandString(10)
-	nsName := acctest.RandString(10)
+	cName := testAccRandomName()
+	tName := testAccRandomName()
+	nsName := testAccRandomName()
 
 	resource.Test(t, resource.TestCase{
 		PreCheck:          func() { testAccPreCheck(t) },
@@ -181,10 +177,10 @@ func TestNamespaceWithUndefinedOptionalsUpdate(t *testing.T) {
 					resource.TestCheckResourceAttr(resourceName, "retention_policies.#", "0"),
 					resource.TestCheckResourceAttr(resourceName, "backlog_quota.#", "0"),
 					resource.TestCheckResourceAttr(resourceName, "namespace_config.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "namespace_config.0.max_producers_per_topic", "50"),
 					resource.TestCheckNoResourceAttr(resourceName, "enable_deduplication"),
 					resource.TestCheckNoResourceAttr(resourceName, "permission_grant.#"),
 				),
-				ExpectNonEmptyPlan: true,
 			},
 		},
 	})
@@ -193,9 +189,9 @@ func TestNamespaceWithUndefinedOptionalsUpdate(t *testing.T) {
 func TestNamespaceWithPermissionGrantUpdate(t *testing.T) {
 
 	resourceName := "pulsar_namespace.test"
-	cName := acctest.RandString(10)
-	tName := acctest.RandString(10)
-	nsName := acctest.RandString(10)
+	cName := testAccRandomName()
+	tName := testAccRandomName()
+	nsName := testAccRandomName()
 
 	resource.Test(t, resource.TestCase{
 		PreCheck:          func() { testAccPreCheck(t) },
@@ -256,9 +252,9 @@ func TestNamespaceWithPermissionGrantUpdate(t *testing.T) {
 func TestNamespaceWithTopicAutoCreationUpdate(t *testing.T) {
 
 	resourceName := "pulsar_namespace.test"
-	cName := acctest.RandString(10)
-	tName := acctest.RandString(10)
-	nsName := acctest.RandString(10)
+	cName := testAccRandomName()
+	tName := testAccRandomName()
+	nsName := testAccRandomName()
 
 	resource.Test(t, resource.TestCase{
 		PreCheck:          func() { testAccPreCheck(t) },
@@ -322,9 +318,117 @@ func TestNamespaceWithTopicAutoCreationUpdate(t *testing.T) {
 	})
 }
 
+func TestNamespaceWithOffloadPoliciesUpdate(t *testing.T) {
+
+	resourceName := "pulsar_namespace.test"
+	cName := testAccRandomName()
+	tName := testAccRandomName()
+	nsName := testAccRandomName()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		IDRefreshName:     resourceName,
+		CheckDestroy:      testPulsarNamespaceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testPulsarNamespaceWithOffloadPolicies(testWebServiceURL, cName, tName, nsName,
+					`offload_policies {
+						driver                    = "aws-s3"
+						bucket                    = "pulsar-offload"
+						region                    = "eu-west-1"
+						credential_id             = "id"
+						credential_secret         = "secret"
+						threshold_size_in_bytes   = 1024
+						threshold_time_in_seconds = 3600
+						deletion_lag_ms           = 1000
+						read_priority             = "tiered-storage-first"
+					}`),
+				Check: resource.ComposeTestCheckFunc(
+					testPulsarNamespaceExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "offload_policies.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "offload_policies.0.driver", "aws-s3"),
+					resource.TestCheckResourceAttr(resourceName, "offload_policies.0.bucket", "pulsar-offload"),
+					resource.TestCheckResourceAttr(resourceName, "offload_policies.0.credential_secret", "secret"),
+					resource.TestCheckResourceAttr(resourceName, "offload_policies.0.threshold_time_in_seconds", "3600"),
+					resource.TestCheckResourceAttr(resourceName, "offload_policies.0.read_priority",
+						"tiered-storage-first"),
+				),
+			},
+			{
+				Config: testPulsarNamespaceWithOffloadPolicies(testWebServiceURL, cName, tName, nsName,
+					`offload_policies {
+						driver = "aws-s3"
+						bucket = "pulsar-offload-2"
+						region = "eu-west-1"
+					}`),
+				Check: resource.ComposeTestCheckFunc(
+					testPulsarNamespaceExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "offload_policies.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "offload_policies.0.bucket", "pulsar-offload-2"),
+					resource.TestCheckResourceAttr(resourceName, "offload_policies.0.region", "eu-west-1"),
+				),
+			},
+			{
+				Config: testPulsarNamespaceWithoutOptionals(testWebServiceURL, cName, tName, nsName),
+				Check: resource.ComposeTestCheckFunc(
+					testPulsarNamespaceExists(resourceName),
+					resource.TestCheckNoResourceAttr(resourceName, "offload_policies.#"),
+				),
+			},
+		},
+	})
+}
+
+func TestNamespaceWithSubscriptionPermissionUpdate(t *testing.T) {
+
+	resourceName := "pulsar_namespace.test"
+	cName := testAccRandomName()
+	tName := testAccRandomName()
+	nsName := testAccRandomName()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		IDRefreshName:     resourceName,
+		CheckDestroy:      testPulsarNamespaceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testPulsarNamespaceWithTopicAutoCreation(testWebServiceURL, cName, tName, nsName,
+					`subscription_permission {
+						subscription = "sub-1"
+						roles        = ["role-1", "role-2"]
+					}
+					subscription_permission {
+						subscription = "sub-2"
+						roles        = ["role-3"]
+					}`),
+				Check: resource.ComposeTestCheckFunc(
+					testPulsarNamespaceExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "subscription_permission.#", "2"),
+				),
+			},
+			{
+				Config: testPulsarNamespaceWithTopicAutoCreation(testWebServiceURL, cName, tName, nsName,
+					`subscription_permission {
+						subscription = "sub-1"
+						roles        = ["role-2"]
+					}`),
+				Check: resource.ComposeTestCheckFunc(
+					testPulsarNamespaceExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "subscription_permission.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "subscription_permission.0.subscription", "sub-1"),
+					resource.TestCheckResourceAttr(resourceName, "subscription_permission.0.roles.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "subscription_permission.0.roles.0", "role-2"),
+				),
+			},
+		},
+	})
+}
+
 func TestImportExistingNamespace(t *testing.T) {
 	tname := "public"
-	ns := acctest.RandString(10)
+	ns := testAccRandomName()
 
 	id := tname + "/" + ns
 
@@ -665,3 +769,216 @@ resource "pulsar_namespace" "test" {
 }
 `, wsURL, cluster, tenant, ns, topicAutoCreation)
 }
+
+func testPulsarNamespaceWithOffloadPolicies(wsURL, cluster, tenant, ns string, offloadPolicies string) string {
+	return testPulsarNamespaceWithTopicAutoCreation(wsURL, cluster, tenant, ns, offloadPolicies)
+}
+
+func TestNamespaceUnit(t *testing.T) {
+	fake := newFakeAdminServer(t)
+	resourceName := "pulsar_namespace.test"
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testUnitPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: fake.checkDestroyed("pulsar_namespace", func(id string) string {
+			return "/admin/v2/namespaces/" + id
+		}),
+		Steps: []resource.TestStep{
+			{
+				Config: testPulsarNamespaceUnit(fake.URL, 1600),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "id", "public/orders"),
+					resource.TestCheckResourceAttr(resourceName, "permission_grant.#", "1"),
+					func(s *terraform.State) error {
+						var actions []string
+						if !fake.get("/admin/v2/namespaces/public/orders/permissions/app", &actions) {
+							return fmt.Errorf("expected the permissions of app to be granted")
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config: testPulsarNamespaceUnit(fake.URL, 60),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "retention_policies.*", map[string]string{
+						"retention_minutes": "60",
+					}),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateId:     "public/orders",
+				ImportStateVerify: true,
+				// the policies are only read back when they are configured
+				ImportStateVerifyIgnore: []string{"retention_policies", "permission_grant"},
+			},
+			{
+				PreConfig: func() {
+					fake.put("/admin/v2/namespaces/public/orders/retention", map[string]int{
+						"retentionTimeInMinutes": 5,
+						"retentionSizeInMB":      1000,
+					})
+				},
+				Config:             testPulsarNamespaceUnit(fake.URL, 60),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testPulsarNamespaceUnit(url string, retentionMinutes int) string {
+	return testFakeProvider(url) + fmt.Sprintf(`
+resource "pulsar_namespace" "test" {
+  tenant    = "public"
+  namespace = "orders"
+
+  retention_policies {
+    retention_minutes    = "%d"
+    retention_size_in_mb = "1000"
+  }
+
+  permission_grant {
+    role    = "app"
+    actions = ["produce", "consume"]
+  }
+}
+`, retentionMinutes)
+}
+
+func TestNamespaceConfigUnit(t *testing.T) {
+	fake := newFakeAdminServer(t)
+	resourceName := "pulsar_namespace.test"
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testUnitPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: fake.checkDestroyed("pulsar_namespace", func(id string) string {
+			return "/admin/v2/namespaces/" + id
+		}),
+		Steps: []resource.TestStep{
+			{
+				Config: testPulsarNamespaceConfigUnit(fake.URL, 50),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "namespace_config.0.max_producers_per_topic", "50"),
+					func(s *terraform.State) error {
+						// the limits which are not configured are left to the brokers
+						for _, policy := range []string{"maxConsumersPerTopic", "maxConsumersPerSubscription", "messageTTL"} {
+							if fake.exists("/admin/v2/namespaces/public/orders/" + policy) {
+								return fmt.Errorf("expected %s to be left unset", policy)
+							}
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config:   testPulsarNamespaceConfigUnit(fake.URL, 50),
+				PlanOnly: true,
+			},
+			{
+				Config: testPulsarNamespaceConfigUnit(fake.URL, 0),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "namespace_config.0.max_producers_per_topic", "0"),
+					func(s *terraform.State) error {
+						var maxProducers int
+						fake.get("/admin/v2/namespaces/public/orders/maxProducersPerTopic", &maxProducers)
+						if maxProducers != 0 {
+							return fmt.Errorf("expected the max producers to be set to 0, got %d", maxProducers)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				PreConfig: func() {
+					fake.put("/admin/v2/namespaces/public/orders/maxProducersPerTopic", 10)
+				},
+				Config:             testPulsarNamespaceConfigUnit(fake.URL, 0),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testPulsarNamespaceConfigUnit(fake.URL, 50),
+			},
+			{
+				// the limits removed from the configuration are removed from the namespace
+				Config: testPulsarNamespaceConfigLimitsUnit(fake.URL, `
+    message_ttl_seconds = 60`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "namespace_config.0.max_producers_per_topic", "0"),
+					resource.TestCheckResourceAttr(resourceName, "namespace_config.0.anti_affinity", ""),
+					func(s *terraform.State) error {
+						for _, policy := range []string{"maxProducersPerTopic", "antiAffinity"} {
+							if fake.exists("/admin/v2/namespaces/public/orders/" + policy) {
+								return fmt.Errorf("expected %s to be removed", policy)
+							}
+						}
+						return nil
+					},
+				),
+			},
+			{
+				// so are they when the whole block is removed
+				Config: testPulsarNamespaceConfigLimitsUnit(fake.URL, ""),
+				Check: func(s *terraform.State) error {
+					if fake.exists("/admin/v2/namespaces/public/orders/messageTTL") {
+						return fmt.Errorf("expected messageTTL to be removed")
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func testPulsarNamespaceConfigUnit(url string, maxProducers int) string {
+	return testPulsarNamespaceConfigLimitsUnit(url, fmt.Sprintf(`
+    anti_affinity           = "anti-aff"
+    max_producers_per_topic = %d`, maxProducers))
+}
+
+func testPulsarNamespaceConfigLimitsUnit(url, limits string) string {
+	namespaceConfig := ""
+	if limits != "" {
+		namespaceConfig = fmt.Sprintf(`
+  namespace_config {%s
+  }`, limits)
+	}
+
+	return testFakeProvider(url) + fmt.Sprintf(`
+resource "pulsar_namespace" "test" {
+  tenant    = "public"
+  namespace = "orders"
+%s
+}
+`, namespaceConfig)
+}
+
+func TestNamespaceStateUpgradeV0(t *testing.T) {
+	rawState := map[string]interface{}{
+		"namespace_config": []interface{}{
+			map[string]interface{}{
+				"anti_affinity":           "anti-aff",
+				"max_producers_per_topic": float64(50),
+				"message_ttl_seconds":     float64(-1),
+			},
+		},
+	}
+
+	state, err := resourcePulsarNamespaceStateUpgradeV0(context.Background(), rawState, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	namespaceConfig := state["namespace_config"].([]interface{})[0].(map[string]interface{})
+	if namespaceConfig["message_ttl_seconds"] != nil {
+		t.Errorf("expected the unset message ttl to be dropped, got %v", namespaceConfig["message_ttl_seconds"])
+	}
+	if namespaceConfig["max_producers_per_topic"] != float64(50) {
+		t.Errorf("expected the max producers to be kept, got %v", namespaceConfig["max_producers_per_topic"])
+	}
+}
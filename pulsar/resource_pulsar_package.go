@@ -0,0 +1,296 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsar
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/rest"
+	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/utils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	resourcePackageTypeKey          = "type"
+	resourcePackageTenantKey        = "tenant"
+	resourcePackageNamespaceKey     = "namespace"
+	resourcePackageNameKey          = "name"
+	resourcePackageVersionKey       = "version"
+	resourcePackagePathKey          = "path"
+	resourcePackageDescriptionKey   = "description"
+	resourcePackageContactKey       = "contact"
+	resourcePackagePropertiesKey    = "properties"
+	resourcePackageURLKey           = "package_url"
+	resourcePackageArchiveSHA256Key = "archive_sha256"
+)
+
+var resourcePackageDescriptions = make(map[string]string)
+
+func init() {
+	//nolint:lll
+	resourcePackageDescriptions = map[string]string{
+		resourcePackageTypeKey:          "The type of the package. Possible values are `function`, `sink` and `source`.",
+		resourcePackageTenantKey:        "The tenant of the package.",
+		resourcePackageNamespaceKey:     "The namespace of the package.",
+		resourcePackageNameKey:          "The name of the package.",
+		resourcePackageVersionKey:       "The version of the package. Packages are immutable, a new version has to be uploaded to change the content.",
+		resourcePackagePathKey:          "The path to the local file to upload.",
+		resourcePackageDescriptionKey:   "The description of the package.",
+		resourcePackageContactKey:       "The contact information of the package.",
+		resourcePackagePropertiesKey:    "User-defined properties of the package.",
+		resourcePackageURLKey:           "The package url, e.g. `function://tenant/namespace/name@version`, to reference from functions, sinks and sources.",
+		resourcePackageArchiveSHA256Key: "The sha256 of the uploaded file, a content change replaces the package.",
+	}
+}
+
+func resourcePulsarPackage() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourcePulsarPackageCreate,
+		ReadContext:   resourcePulsarPackageRead,
+		UpdateContext: resourcePulsarPackageUpdate,
+		DeleteContext: resourcePulsarPackageDelete,
+		CustomizeDiff: customdiff.All(
+			customizeDiffArchiveSHA256(resourcePackageArchiveSHA256Key, resourcePackagePathKey),
+			// an imported package has no known hash yet, only replace packages whose content really changed
+			customdiff.ForceNewIfChange(resourcePackageArchiveSHA256Key, func(ctx context.Context, old, new,
+				meta interface{}) bool {
+				return old.(string) != "" && new.(string) != ""
+			}),
+		),
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+				packageName, err := utils.GetPackageName(d.Id())
+				if err != nil {
+					return nil, fmt.Errorf("ERROR_PARSE_PACKAGE_NAME: %w", err)
+				}
+
+				_ = d.Set(resourcePackageTypeKey, packageName.GetType().String())
+				_ = d.Set(resourcePackageTenantKey, packageName.GetTenant())
+				_ = d.Set(resourcePackageNamespaceKey, packageName.GetNamespace())
+				_ = d.Set(resourcePackageNameKey, packageName.GetName())
+				_ = d.Set(resourcePackageVersionKey, packageName.GetVersion())
+
+				diags := resourcePulsarPackageRead(ctx, d, meta)
+				if diags.HasError() {
+					return nil, fmt.Errorf("import %q: %s", d.Id(), diags[0].Summary)
+				}
+				if d.Id() == "" {
+					return nil, fmt.Errorf("import %q: package not found", packageName.String())
+				}
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+		Schema: map[string]*schema.Schema{
+			resourcePackageTypeKey: {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: resourcePackageDescriptions[resourcePackageTypeKey],
+				ValidateFunc: validation.StringInSlice([]string{
+					utils.PackageTypeFunction.String(),
+					utils.PackageTypeSink.String(),
+					utils.PackageTypeSource.String(),
+				}, false),
+			},
+			resourcePackageTenantKey: {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: resourcePackageDescriptions[resourcePackageTenantKey],
+			},
+			resourcePackageNamespaceKey: {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: resourcePackageDescriptions[resourcePackageNamespaceKey],
+			},
+			resourcePackageNameKey: {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: resourcePackageDescriptions[resourcePackageNameKey],
+			},
+			resourcePackageVersionKey: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  resourcePackageDescriptions[resourcePackageVersionKey],
+				ValidateFunc: validateNotBlank,
+			},
+			resourcePackagePathKey: {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  resourcePackageDescriptions[resourcePackagePathKey],
+				ValidateFunc: validateNotBlank,
+			},
+			resourcePackageDescriptionKey: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: resourcePackageDescriptions[resourcePackageDescriptionKey],
+			},
+			resourcePackageContactKey: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: resourcePackageDescriptions[resourcePackageContactKey],
+			},
+			resourcePackagePropertiesKey: {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Description: resourcePackageDescriptions[resourcePackagePropertiesKey],
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			resourcePackageURLKey: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: resourcePackageDescriptions[resourcePackageURLKey],
+			},
+			resourcePackageArchiveSHA256Key: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: resourcePackageDescriptions[resourcePackageArchiveSHA256Key],
+			},
+		},
+	}
+}
+
+func resourcePulsarPackageCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := getV3ClientFromMeta(meta).Packages()
+
+	packageName, err := unmarshalPackageName(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	path := d.Get(resourcePackagePathKey).(string)
+	archiveSum, err := archiveSHA256(path)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("ERROR_READ_ARCHIVE: %w", err))
+	}
+
+	err = client.Upload(packageName.String(), path,
+		d.Get(resourcePackageDescriptionKey).(string),
+		d.Get(resourcePackageContactKey).(string),
+		unmarshalPackageProperties(d))
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("ERROR_CREATE_PACKAGE: %w", err))
+	}
+
+	_ = d.Set(resourcePackageArchiveSHA256Key, archiveSum)
+
+	return resourcePulsarPackageRead(ctx, d, meta)
+}
+
+func resourcePulsarPackageRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := getV3ClientFromMeta(meta).Packages()
+
+	packageName, err := unmarshalPackageName(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	metadata, err := client.GetMetadata(packageName.String())
+	if err != nil {
+		if cliErr, ok := err.(rest.Error); ok && cliErr.Code == 404 {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(fmt.Errorf("ERROR_READ_PACKAGE: %w", err))
+	}
+
+	d.SetId(packageName.String())
+	_ = d.Set(resourcePackageURLKey, packageName.String())
+	_ = d.Set(resourcePackageDescriptionKey, metadata.Description)
+	_ = d.Set(resourcePackageContactKey, metadata.Contact)
+	_ = d.Set(resourcePackagePropertiesKey, metadata.Properties)
+
+	return nil
+}
+
+func resourcePulsarPackageUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := getV3ClientFromMeta(meta).Packages()
+
+	packageName, err := unmarshalPackageName(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.HasChanges(resourcePackageDescriptionKey, resourcePackageContactKey, resourcePackagePropertiesKey) {
+		err = client.UpdateMetadata(packageName.String(),
+			d.Get(resourcePackageDescriptionKey).(string),
+			d.Get(resourcePackageContactKey).(string),
+			unmarshalPackageProperties(d))
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("ERROR_UPDATE_PACKAGE: %w", err))
+		}
+	}
+
+	// only reached for imported packages, whose content cannot be compared with the local file
+	if d.HasChange(resourcePackageArchiveSHA256Key) {
+		archiveSum, err := archiveSHA256(d.Get(resourcePackagePathKey).(string))
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("ERROR_READ_ARCHIVE: %w", err))
+		}
+		_ = d.Set(resourcePackageArchiveSHA256Key, archiveSum)
+	}
+
+	return resourcePulsarPackageRead(ctx, d, meta)
+}
+
+func resourcePulsarPackageDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := getV3ClientFromMeta(meta).Packages()
+
+	packageName, err := unmarshalPackageName(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err = client.Delete(packageName.String()); err != nil {
+		if cliErr, ok := err.(rest.Error); ok && cliErr.Code == 404 {
+			return nil
+		}
+		return diag.FromErr(fmt.Errorf("ERROR_DELETE_PACKAGE: %w", err))
+	}
+
+	return nil
+}
+
+func unmarshalPackageName(d *schema.ResourceData) (*utils.PackageName, error) {
+	packageName, err := utils.GetPackageNameWithComponents(
+		utils.PackageType(d.Get(resourcePackageTypeKey).(string)),
+		d.Get(resourcePackageTenantKey).(string),
+		d.Get(resourcePackageNamespaceKey).(string),
+		d.Get(resourcePackageNameKey).(string),
+		d.Get(resourcePackageVersionKey).(string))
+	if err != nil {
+		return nil, fmt.Errorf("ERROR_PARSE_PACKAGE_NAME: %w", err)
+	}
+
+	return packageName, nil
+}
+
+func unmarshalPackageProperties(d *schema.ResourceData) map[string]string {
+	properties := make(map[string]string)
+	for k, v := range d.Get(resourcePackagePropertiesKey).(map[string]interface{}) {
+		properties[k] = v.(string)
+	}
+	return properties
+}
@@ -0,0 +1,147 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsar
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/rest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func init() {
+	initTestWebServiceURL()
+}
+
+func TestPackage(t *testing.T) {
+	resourceName := "pulsar_package.test"
+	name := acctest.RandString(10)
+	path := filepath.Join(t.TempDir(), "package.txt")
+	packageURL := fmt.Sprintf("function://public/default/%s@v1", name)
+
+	writePackageFile := func(content string) {
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("ERROR_WRITING_PACKAGE_FILE: %v", err)
+		}
+	}
+	writePackageFile("v1")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testPulsarPackageDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testPulsarPackage(testWebServiceURL, name, path, "first"),
+				Check: resource.ComposeTestCheckFunc(
+					testPulsarPackageExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "package_url", packageURL),
+					resource.TestCheckResourceAttr(resourceName, "description", "first"),
+					resource.TestCheckResourceAttr(resourceName, "properties.team", "data"),
+					resource.TestCheckResourceAttrSet(resourceName, "archive_sha256"),
+				),
+			},
+			{
+				Config: testPulsarPackage(testWebServiceURL, name, path, "second"),
+				Check: resource.ComposeTestCheckFunc(
+					testPulsarPackageExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "description", "second"),
+				),
+			},
+			{
+				PreConfig: func() { writePackageFile("v1 with changes") },
+				Config:    testPulsarPackage(testWebServiceURL, name, path, "second"),
+				Check: resource.ComposeTestCheckFunc(
+					testPulsarPackageExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "package_url", packageURL),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateId:           packageURL,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"path", "archive_sha256"},
+			},
+		},
+	})
+}
+
+func testPulsarPackageExists(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("NOT_FOUND: %s", name)
+		}
+
+		client := getV3ClientFromMeta(testAccProvider.Meta()).Packages()
+		if _, err := client.GetMetadata(rs.Primary.ID); err != nil {
+			return fmt.Errorf("ERROR_READ_PACKAGE: %w", err)
+		}
+
+		return nil
+	}
+}
+
+func testPulsarPackageDestroy(s *terraform.State) error {
+	client := getV3ClientFromMeta(testAccProvider.Meta()).Packages()
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "pulsar_package" {
+			continue
+		}
+
+		_, err := client.GetMetadata(rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("ERROR_RESOURCE_PACKAGE_STILL_EXISTS: %s", rs.Primary.ID)
+		}
+		if cliErr, ok := err.(rest.Error); !ok || cliErr.Code != 404 {
+			return fmt.Errorf("ERROR_READ_PACKAGE: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func testPulsarPackage(url, name, path, description string) string {
+	return fmt.Sprintf(`
+provider "pulsar" {
+  web_service_url = "%s"
+}
+
+resource "pulsar_package" "test" {
+  type        = "function"
+  tenant      = "public"
+  namespace   = "default"
+  name        = "%s"
+  version     = "v1"
+  path        = "%s"
+  description = "%s"
+  contact     = "data-team"
+
+  properties = {
+    team = "data"
+  }
+}
+`, url, name, path, description)
+}
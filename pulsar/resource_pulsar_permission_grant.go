@@ -0,0 +1,242 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/rest"
+	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/utils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// permissionGrantIDSeparator separates the namespace or topic from the role in the resource id,
+// e.g. public/default#some-role or persistent://public/default/topic#some-role
+const permissionGrantIDSeparator = "#"
+
+func resourcePulsarPermissionGrant() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourcePulsarPermissionGrantCreate,
+		ReadContext:   resourcePulsarPermissionGrantRead,
+		UpdateContext: resourcePulsarPermissionGrantUpdate,
+		DeleteContext: resourcePulsarPermissionGrantDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourcePulsarPermissionGrantImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"namespace": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Description:  descriptions["permission_grant_namespace"],
+				ExactlyOneOf: []string{"namespace", "topic"},
+				ValidateFunc: validateNotBlank,
+			},
+			"topic": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Description:  descriptions["permission_grant_topic"],
+				ValidateFunc: validateNotBlank,
+			},
+			"role": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  descriptions["permission_grant_role"],
+				ValidateFunc: validateNotBlank,
+			},
+			"actions": {
+				Type:        schema.TypeSet,
+				Required:    true,
+				MinItems:    1,
+				Description: descriptions["permission_grant_actions"],
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateAuthAction,
+				},
+			},
+		},
+	}
+}
+
+func resourcePulsarPermissionGrantImport(ctx context.Context, d *schema.ResourceData,
+	meta interface{}) ([]*schema.ResourceData, error) {
+	idx := strings.LastIndex(d.Id(), permissionGrantIDSeparator)
+	if idx <= 0 || idx == len(d.Id())-1 {
+		return nil, fmt.Errorf("ERROR_PARSE_PERMISSION_GRANT_ID: expected <namespace or topic>%s<role>, got %q",
+			permissionGrantIDSeparator, d.Id())
+	}
+
+	target, role := d.Id()[:idx], d.Id()[idx+1:]
+	if strings.Contains(target, "://") {
+		_ = d.Set("topic", target)
+	} else {
+		_ = d.Set("namespace", target)
+	}
+	_ = d.Set("role", role)
+
+	diags := resourcePulsarPermissionGrantRead(ctx, d, meta)
+	if diags.HasError() {
+		return nil, fmt.Errorf("import %q: %s", d.Id(), diags[0].Summary)
+	}
+	if d.Id() == "" {
+		return nil, fmt.Errorf("import: no permission granted to role %q on %q", role, target)
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourcePulsarPermissionGrantCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if err := grantPermission(d, meta); err != nil {
+		return diag.FromErr(fmt.Errorf("ERROR_CREATE_PERMISSION_GRANT: %w", err))
+	}
+
+	return resourcePulsarPermissionGrantRead(ctx, d, meta)
+}
+
+func resourcePulsarPermissionGrantRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	role := d.Get("role").(string)
+
+	var grants map[string][]utils.AuthAction
+	var target string
+
+	if topic, ok := d.GetOk("topic"); ok {
+		topicName, err := utils.GetTopicName(topic.(string))
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("ERROR_PARSE_TOPIC_NAME: %w", err))
+		}
+		target = topicName.String()
+
+		grants, err = getTopicLevelPermissions(meta, topicName)
+		if err != nil {
+			var cliErr rest.Error
+			if errors.As(err, &cliErr) && cliErr.Code == 404 {
+				d.SetId("")
+				return nil
+			}
+			return diag.FromErr(fmt.Errorf("ERROR_READ_PERMISSION_GRANT: %w", err))
+		}
+	} else {
+		ns, err := utils.GetNamespaceName(d.Get("namespace").(string))
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("ERROR_PARSE_NAMESPACE_NAME: %w", err))
+		}
+		target = ns.String()
+
+		grants, err = getClientFromMeta(meta).Namespaces().GetNamespacePermissions(*ns)
+		if err != nil {
+			if cliErr, ok := err.(rest.Error); ok && cliErr.Code == 404 {
+				d.SetId("")
+				return nil
+			}
+			return diag.FromErr(fmt.Errorf("ERROR_READ_PERMISSION_GRANT: GetNamespacePermissions: %w", err))
+		}
+	}
+
+	actions, found := grants[role]
+	if !found {
+		// the grant was revoked outside of terraform
+		d.SetId("")
+		return nil
+	}
+
+	roleActions := make([]string, 0, len(actions))
+	for _, action := range actions {
+		roleActions = append(roleActions, action.String())
+	}
+
+	_ = d.Set("actions", roleActions)
+	d.SetId(target + permissionGrantIDSeparator + role)
+
+	return nil
+}
+
+func resourcePulsarPermissionGrantUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if err := grantPermission(d, meta); err != nil {
+		return diag.FromErr(fmt.Errorf("ERROR_UPDATE_PERMISSION_GRANT: %w", err))
+	}
+
+	return resourcePulsarPermissionGrantRead(ctx, d, meta)
+}
+
+func resourcePulsarPermissionGrantDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	role := d.Get("role").(string)
+
+	var err error
+	if topic, ok := d.GetOk("topic"); ok {
+		topicName, parseErr := utils.GetTopicName(topic.(string))
+		if parseErr != nil {
+			return diag.FromErr(fmt.Errorf("ERROR_PARSE_TOPIC_NAME: %w", parseErr))
+		}
+		err = getClientFromMeta(meta).Topics().RevokePermission(*topicName, role)
+	} else {
+		ns, parseErr := utils.GetNamespaceName(d.Get("namespace").(string))
+		if parseErr != nil {
+			return diag.FromErr(fmt.Errorf("ERROR_PARSE_NAMESPACE_NAME: %w", parseErr))
+		}
+		err = getClientFromMeta(meta).Namespaces().RevokeNamespacePermission(*ns, role)
+	}
+
+	if err != nil {
+		// the namespace or topic is already gone together with its grants
+		if cliErr, ok := err.(rest.Error); ok && cliErr.Code == 404 {
+			return nil
+		}
+		return diag.FromErr(fmt.Errorf("ERROR_DELETE_PERMISSION_GRANT: %w", err))
+	}
+
+	return nil
+}
+
+func grantPermission(d *schema.ResourceData, meta interface{}) error {
+	role := d.Get("role").(string)
+
+	actions := make([]utils.AuthAction, 0)
+	for _, action := range d.Get("actions").(*schema.Set).List() {
+		authAction, err := utils.ParseAuthAction(action.(string))
+		if err != nil {
+			return fmt.Errorf("ERROR_INVALID_AUTH_ACTION: %w", err)
+		}
+		actions = append(actions, authAction)
+	}
+
+	if topic, ok := d.GetOk("topic"); ok {
+		topicName, err := utils.GetTopicName(topic.(string))
+		if err != nil {
+			return fmt.Errorf("ERROR_PARSE_TOPIC_NAME: %w", err)
+		}
+		if err = getClientFromMeta(meta).Topics().GrantPermission(*topicName, role, actions); err != nil {
+			return fmt.Errorf("GrantPermission: %w", err)
+		}
+		return nil
+	}
+
+	ns, err := utils.GetNamespaceName(d.Get("namespace").(string))
+	if err != nil {
+		return fmt.Errorf("ERROR_PARSE_NAMESPACE_NAME: %w", err)
+	}
+	if err = getClientFromMeta(meta).Namespaces().GrantNamespacePermission(*ns, role, actions); err != nil {
+		return fmt.Errorf("GrantNamespacePermission: %w", err)
+	}
+
+	return nil
+}
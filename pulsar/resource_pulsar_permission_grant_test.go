@@ -0,0 +1,327 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsar
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/utils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/streamnative/terraform-provider-pulsar/types"
+)
+
+func init() {
+	initTestWebServiceURL()
+}
+
+func TestNamespacePermissionGrant(t *testing.T) {
+	resourceName := "pulsar_permission_grant.test"
+	role := acctest.RandString(10)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testPulsarPermissionGrantDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testPulsarPermissionGrant(testWebServiceURL, "namespace", "public/default", role,
+					`["produce", "consume"]`),
+				Check: resource.ComposeTestCheckFunc(
+					testPulsarPermissionGrantExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "id", "public/default#"+role),
+					resource.TestCheckResourceAttr(resourceName, "actions.#", "2"),
+				),
+			},
+			{
+				Config: testPulsarPermissionGrant(testWebServiceURL, "namespace", "public/default", role,
+					`["consume"]`),
+				Check: resource.ComposeTestCheckFunc(
+					testPulsarPermissionGrantExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "actions.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "actions.0", "consume"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateId:     "public/default#" + role,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestTopicPermissionGrant(t *testing.T) {
+	resourceName := "pulsar_permission_grant.test"
+	role := acctest.RandString(10)
+	topic := "persistent://public/default/" + testAccRandomName()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			createTopic(t, topic, 0)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testPulsarPermissionGrantDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testPulsarPermissionGrant(testWebServiceURL, "topic", topic, role, `["produce"]`),
+				Check: resource.ComposeTestCheckFunc(
+					testPulsarPermissionGrantExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "id", topic+"#"+role),
+					resource.TestCheckResourceAttr(resourceName, "actions.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "actions.0", "produce"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateId:     topic + "#" + role,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func getPermissionGrants(state *terraform.ResourceState) (map[string][]utils.AuthAction, error) {
+	if topic, ok := state.Primary.Attributes["topic"]; ok && topic != "" {
+		topicName, err := utils.GetTopicName(topic)
+		if err != nil {
+			return nil, err
+		}
+		return getClientFromMeta(testAccProvider.Meta()).Topics().GetPermissions(*topicName)
+	}
+
+	ns, err := utils.GetNamespaceName(state.Primary.Attributes["namespace"])
+	if err != nil {
+		return nil, err
+	}
+	return getClientFromMeta(testAccProvider.Meta()).Namespaces().GetNamespacePermissions(*ns)
+}
+
+func testPulsarPermissionGrantExists(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("NOT_FOUND: %s", name)
+		}
+
+		grants, err := getPermissionGrants(rs)
+		if err != nil {
+			return fmt.Errorf("ERROR_READ_PERMISSION_GRANT: %w", err)
+		}
+
+		if _, ok := grants[rs.Primary.Attributes["role"]]; !ok {
+			return fmt.Errorf("ERROR_PERMISSION_GRANT_NOT_FOUND: %s", rs.Primary.ID)
+		}
+
+		return nil
+	}
+}
+
+func testPulsarPermissionGrantDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "pulsar_permission_grant" {
+			continue
+		}
+
+		grants, err := getPermissionGrants(rs)
+		if err != nil {
+			return nil
+		}
+
+		if _, ok := grants[rs.Primary.Attributes["role"]]; ok {
+			return fmt.Errorf("ERROR_RESOURCE_PERMISSION_GRANT_STILL_EXISTS: %s", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func testPulsarPermissionGrant(url, targetKey, target, role, actions string) string {
+	return fmt.Sprintf(`
+provider "pulsar" {
+  web_service_url = "%s"
+}
+
+resource "pulsar_permission_grant" "test" {
+  %s = "%s"
+  role    = "%s"
+  actions = %s
+}
+`, url, targetKey, target, role, actions)
+}
+
+func TestPermissionGrantUnit(t *testing.T) {
+	fake := newFakeAdminServer(t)
+	resourceName := "pulsar_permission_grant.test"
+	grantPath := "/admin/v2/namespaces/public/default/permissions/app"
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testUnitPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: fake.checkDestroyed("pulsar_permission_grant", func(id string) string {
+			return grantPath
+		}),
+		Steps: []resource.TestStep{
+			{
+				Config: testPulsarPermissionGrant(fake.URL, "namespace", "public/default", "app",
+					`["produce", "consume"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "id", "public/default#app"),
+					resource.TestCheckResourceAttr(resourceName, "actions.#", "2"),
+				),
+			},
+			{
+				Config: testPulsarPermissionGrant(fake.URL, "namespace", "public/default", "app", `["consume"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "actions.#", "1"),
+					func(s *terraform.State) error {
+						var actions []string
+						fake.get(grantPath, &actions)
+						if len(actions) != 1 || actions[0] != "consume" {
+							return fmt.Errorf("expected the actions [consume], got %v", actions)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateId:     "public/default#app",
+				ImportStateVerify: true,
+			},
+			{
+				PreConfig:          func() { fake.put(grantPath, []string{"produce"}) },
+				Config:             testPulsarPermissionGrant(fake.URL, "namespace", "public/default", "app", `["consume"]`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				PreConfig:          func() { fake.remove(grantPath) },
+				Config:             testPulsarPermissionGrant(fake.URL, "namespace", "public/default", "app", `["consume"]`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestTopicPermissionGrantUnit(t *testing.T) {
+	fake := newFakeAdminServer(t)
+	topic := "persistent://public/default/orders"
+	grantPath := "/admin/v2/persistent/public/default/orders/permissions/app"
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck: func() {
+			testUnitPreCheck(t)
+			fake.put("/admin/v2/persistent/public/default/orders", map[string]int{"partitions": 0})
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: fake.checkDestroyed("pulsar_permission_grant", func(id string) string {
+			return grantPath
+		}),
+		Steps: []resource.TestStep{
+			{
+				Config: testPulsarPermissionGrant(fake.URL, "topic", topic, "app", `["consume"]`),
+				Check:  resource.TestCheckResourceAttr("pulsar_permission_grant.test", "id", topic+"#app"),
+			},
+			{
+				// the namespace grant of the same role does not hide the topic grant revoked out of band
+				PreConfig: func() {
+					fake.put("/admin/v2/namespaces/public/default/permissions/app", []string{"consume"})
+					fake.remove(grantPath)
+				},
+				Config:             testPulsarPermissionGrant(fake.URL, "topic", topic, "app", `["consume"]`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestTopicPermissionGrantInheritedUnit(t *testing.T) {
+	fake := newFakeAdminServer(t)
+	topic := "persistent://public/default/orders"
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck: func() {
+			testUnitPreCheck(t)
+			fake.put("/admin/v2/persistent/public/default/orders", map[string]int{"partitions": 0})
+			fake.put("/admin/v2/namespaces/public/default/permissions/app", []string{"consume"})
+		},
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// the topic grant is read back even though the namespace grants the role the same actions
+				Config: testPulsarPermissionGrant(fake.URL, "topic", topic, "app", `["consume"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("pulsar_permission_grant.test", "id", topic+"#app"),
+					func(*terraform.State) error {
+						if !fake.exists("/admin/v2/persistent/public/default/orders/permissions/app") {
+							return fmt.Errorf("the topic grant was not created")
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config:   testPulsarPermissionGrant(fake.URL, "topic", topic, "app", `["consume"]`),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestDiffPermissionGrants(t *testing.T) {
+	consume, produce := utils.AuthAction("consume"), utils.AuthAction("produce")
+	current := map[string][]utils.AuthAction{
+		"changed":   {consume},
+		"unchanged": {produce},
+		"removed":   {produce},
+		"unmanaged": {produce},
+	}
+	desired := []*types.PermissionGrant{
+		{Role: "changed", Actions: []utils.AuthAction{consume, produce}},
+		{Role: "unchanged", Actions: []utils.AuthAction{produce}},
+		{Role: "added", Actions: []utils.AuthAction{consume}},
+	}
+
+	revokes, grants := diffPermissionGrants(current, desired, map[string]bool{"removed": true}, false)
+
+	// the grant replaces the actions of a changed role, revoking it first would leave it without access
+	if fmt.Sprint(revokes) != "[removed]" {
+		t.Fatalf("unexpected revokes: %v", revokes)
+	}
+	granted := []string{}
+	for _, grant := range grants {
+		granted = append(granted, grant.Role)
+	}
+	if fmt.Sprint(granted) != "[changed added]" {
+		t.Fatalf("unexpected grants: %v", granted)
+	}
+
+	revokes, _ = diffPermissionGrants(current, desired, nil, true)
+	if fmt.Sprint(revokes) != "[removed unmanaged]" {
+		t.Fatalf("unexpected authoritative revokes: %v", revokes)
+	}
+}
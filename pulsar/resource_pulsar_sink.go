@@ -26,6 +26,7 @@ import (
 	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/rest"
 	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/utils"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/pkg/errors"
 
@@ -58,6 +59,8 @@ const (
 	resourceSinkRAMKey                               = "ram_mb"
 	resourceSinkDiskKey                              = "disk_mb"
 	resourceSinkConfigsKey                           = "configs"
+	resourceSinkConfigsMapKey                        = "configs_map"
+	resourceSinkSensitiveConfigsKey                  = "sensitive_configs"
 	resourceSinkAutoACKKey                           = "auto_ack"
 	resourceSinkTimeoutKey                           = "timeout_ms"
 	resourceSinkCustomRuntimeOptionsKey              = "custom_runtime_options"
@@ -67,10 +70,34 @@ const (
 	resourceSinkRetainKeyOrderingKey                 = "retain_key_ordering"
 	resourceSinkSinkTypeKey                          = "sink_type"
 	resourceSinkSecretsKey                           = "secrets"
+	resourceSinkArchiveSHA256Key                     = "archive_sha256"
+	resourceSinkDesiredStateKey                      = "desired_state"
+	resourceSinkRestartTriggerKey                    = "restart_trigger"
+	resourceSinkSecretKey                            = "secret"
+	resourceSinkWaitForRunningKey                    = "wait_for_running"
 )
 
 var resourceSinkDescriptions = make(map[string]string)
 
+var resourceSinkConnectorConfigsKeys = connectorConfigsKeys{
+	configs:          resourceSinkConfigsKey,
+	configsMap:       resourceSinkConfigsMapKey,
+	sensitiveConfigs: resourceSinkSensitiveConfigsKey,
+}
+
+var resourceSinkInstanceConfigKeys = instanceConfigKeys{
+	inputs: []string{resourceSinkInputsKey, resourceSinkTopicsPatternKey, resourceSinkInputSpecsKey,
+		resourceSinkCustomSerdeInputsKey, resourceSinkCustomSchemaInputsKey},
+	topics: []string{resourceSinkInputsKey, resourceSinkInputSpecsKey, resourceSinkCustomSerdeInputsKey,
+		resourceSinkCustomSchemaInputsKey, resourceSinkDeadLetterTopicKey},
+	effectivelyOnceConflicts: []string{resourceSinkRetainKeyOrderingKey},
+	processingGuarantees:     resourceSinkProcessingGuaranteesKey,
+	retainOrdering:           resourceSinkRetainOrderingKey,
+	retainKeyOrdering:        resourceSinkRetainKeyOrderingKey,
+	maxMessageRetries:        resourceSinkMaxRedeliverCountKey,
+	deadLetterTopic:          resourceSinkDeadLetterTopicKey,
+}
+
 func init() {
 	//nolint:lll
 	resourceSinkDescriptions = map[string]string{
@@ -93,7 +120,9 @@ func init() {
 		resourceSinkCPUKey:                          "The CPU that needs to be allocated per sink instance (applicable only to Docker runtime)",
 		resourceSinkRAMKey:                          "The RAM that need to be allocated per sink instance (applicable only to the process and Docker runtimes)",
 		resourceSinkDiskKey:                         "The disk that need to be allocated per sink instance (applicable only to Docker runtime)",
-		resourceSinkConfigsKey:                      "User defined configs key/values (JSON string)",
+		resourceSinkConfigsKey:                      "User defined configs key/values (JSON string), conflicts with `configs_map`",
+		resourceSinkConfigsMapKey:                   "User defined configs key/values as a map of strings, conflicts with `configs`",
+		resourceSinkSensitiveConfigsKey:             "Sensitive configs key/values, e.g. credentials, merged into the configs on apply and never shown in plans nor read back from the sink",
 		resourceSinkAutoACKKey:                      "Whether or not the framework will automatically acknowledge messages",
 		resourceSinkTimeoutKey:                      "The message timeout in milliseconds",
 		resourceSinkCustomRuntimeOptionsKey:         "A string that encodes options to customize the runtime",
@@ -103,15 +132,27 @@ func init() {
 		resourceSinkRetainKeyOrderingKey:            "Sink consumes and processes messages in key order",
 		resourceSinkSinkTypeKey:                     "The sinks's connector provider",
 		resourceSinkSecretsKey:                      "The map of secretName to an object that encapsulates how the secret is fetched by the underlying secrets provider",
+		resourceSinkArchiveSHA256Key:                "The sha256 of the local archive, used to detect content changes when the archive path stays the same.",
+		resourceSinkDesiredStateKey:                 "The desired state of the sink instances. Possible values are `running` and `stopped`, the actual state is read from the instance status.",
+		resourceSinkRestartTriggerKey:               "Arbitrary key/values that restart the sink instances when changed.",
+		resourceSinkSecretKey:                       "The secrets of the sink fetched by the secrets provider, conflicts with `secrets`.",
+		resourceSinkWaitForRunningKey:               "Whether to wait on create and update until all the sink instances are running, the apply fails with the last instance error when an instance restarts or raises an exception, or when they do not start within the timeout.",
 	}
 }
 
 func resourcePulsarSink() *schema.Resource {
-	return &schema.Resource{
+	resource := &schema.Resource{
 		CreateContext: resourcePulsarSinkCreate,
 		ReadContext:   resourcePulsarSinkRead,
 		UpdateContext: resourcePulsarSinkUpdate,
 		DeleteContext: resourcePulsarSinkDelete,
+		Timeouts:      lifecycleTimeouts(),
+		CustomizeDiff: customdiff.All(
+			customizeDiffArchiveSHA256(resourceSinkArchiveSHA256Key, resourceSinkArchiveKey),
+			customizeDiffSecret(resourceSinkSecretKey),
+			customizeDiffConnectorConfigs(resourceSinkConnectorConfigsKeys),
+			customizeDiffInstanceConfig(resourceSinkInstanceConfigKeys),
+		),
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 				id := d.Id()
@@ -129,6 +170,15 @@ func resourcePulsarSink() *schema.Resource {
 				if diags.HasError() {
 					return nil, fmt.Errorf("import %q: %s", d.Id(), diags[0].Summary)
 				}
+
+				// read all the configs into the JSON string since none of them is configured yet
+				config, err := getV3ClientFromMeta(meta).Sinks().GetSink(parts[0], parts[1], parts[2])
+				if err != nil {
+					return nil, fmt.Errorf("import %q: %w", d.Id(), err)
+				}
+				if err := importConnectorConfigs(d, resourceSinkConnectorConfigsKeys, config.Configs); err != nil {
+					return nil, fmt.Errorf("import %q: %w", d.Id(), err)
+				}
 				return []*schema.ResourceData{d}, nil
 			},
 		},
@@ -300,12 +350,6 @@ func resourcePulsarSink() *schema.Resource {
 				Computed:    true,
 				Description: resourceSinkDescriptions[resourceSinkDiskKey],
 			},
-			resourceSinkConfigsKey: {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Computed:    true,
-				Description: resourceSinkDescriptions[resourceSinkConfigsKey],
-			},
 			resourceSinkAutoACKKey: {
 				Type:        schema.TypeBool,
 				Required:    true,
@@ -349,13 +393,29 @@ func resourcePulsarSink() *schema.Resource {
 				Description: resourceSinkDescriptions[resourceSinkSinkTypeKey],
 			},
 			resourceSinkSecretsKey: {
-				Type:         schema.TypeString,
-				Optional:     true,
-				Description:  resourceSinkDescriptions[resourceSinkSecretsKey],
-				ValidateFunc: jsonValidateFunc,
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{resourceSinkSecretKey},
+				Description:   resourceSinkDescriptions[resourceSinkSecretsKey],
+				ValidateFunc:  jsonValidateFunc,
+			},
+			resourceSinkSecretKey: schemaSecret(resourceSinkDescriptions[resourceSinkSecretKey], resourceSinkSecretsKey),
+			resourceSinkArchiveSHA256Key: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: resourceSinkDescriptions[resourceSinkArchiveSHA256Key],
 			},
+			resourceSinkDesiredStateKey:   schemaDesiredState(resourceSinkDescriptions[resourceSinkDesiredStateKey]),
+			resourceSinkRestartTriggerKey: schemaRestartTrigger(resourceSinkDescriptions[resourceSinkRestartTriggerKey]),
+			resourceSinkWaitForRunningKey: schemaWaitForRunning(resourceSinkDescriptions[resourceSinkWaitForRunningKey]),
 		},
 	}
+
+	for key, value := range schemaConnectorConfigs(resourceSinkConnectorConfigsKeys, resourceSinkDescriptions) {
+		resource.Schema[key] = value
+	}
+
+	return resource
 }
 
 func resourcePulsarSinkCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -366,6 +426,11 @@ func resourcePulsarSinkCreate(ctx context.Context, d *schema.ResourceData, meta
 		return diag.FromErr(err)
 	}
 
+	archiveSum, err := archiveSHA256(sinkConfig.Archive)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("ERROR_READ_ARCHIVE: %w", err))
+	}
+
 	if isPackageURLSupported(sinkConfig.Archive) {
 		err = client.CreateSinkWithURL(sinkConfig, sinkConfig.Archive)
 	} else {
@@ -375,7 +440,11 @@ func resourcePulsarSinkCreate(ctx context.Context, d *schema.ResourceData, meta
 		return diag.FromErr(err)
 	}
 
-	return resourcePulsarSinkRead(ctx, d, meta)
+	// the sink exists from now on, a failed wait taints it instead of leaving it out of the state
+	d.SetId(fmt.Sprintf("%s/%s/%s", sinkConfig.Tenant, sinkConfig.Namespace, sinkConfig.Name))
+	_ = d.Set(resourceSinkArchiveSHA256Key, archiveSum)
+
+	return applySinkLifecycle(ctx, d, meta)
 }
 
 func resourcePulsarSinkRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -497,16 +566,9 @@ func resourcePulsarSinkRead(ctx context.Context, d *schema.ResourceData, meta in
 		}
 	}
 
-	if len(sinkConfig.Configs) != 0 {
-		b, err := json.Marshal(sinkConfig.Configs)
-		if err != nil {
-			return diag.FromErr(errors.Wrap(err, "cannot marshal configs from sinkConfig"))
-		}
-
-		err = d.Set(resourceSinkConfigsKey, string(b))
-		if err != nil {
-			return diag.FromErr(err)
-		}
+	err = flattenConnectorConfigs(d, resourceSinkConnectorConfigsKeys, sinkConfig.Configs)
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
 	err = d.Set(resourceSinkAutoACKKey, sinkConfig.AutoAck)
@@ -565,13 +627,21 @@ func resourcePulsarSinkRead(ctx context.Context, d *schema.ResourceData, meta in
 	}
 
 	if len(sinkConfig.Secrets) != 0 {
-		s, err := json.Marshal(sinkConfig.Secrets)
-		if err != nil {
-			return diag.FromErr(errors.Wrap(err, "cannot marshal secrets from sinkConfig"))
-		}
-		err = d.Set(resourceSinkSecretsKey, string(s))
-		if err != nil {
-			return diag.FromErr(err)
+		secret, structured := flattenSecret(sinkConfig.Secrets)
+		if _, ok := d.GetOk(resourceSinkSecretsKey); !ok && structured {
+			err = d.Set(resourceSinkSecretKey, secret)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+		} else {
+			s, err := json.Marshal(sinkConfig.Secrets)
+			if err != nil {
+				return diag.FromErr(errors.Wrap(err, "cannot marshal secrets from sinkConfig"))
+			}
+			err = d.Set(resourceSinkSecretsKey, string(s))
+			if err != nil {
+				return diag.FromErr(err)
+			}
 		}
 	}
 
@@ -594,30 +664,98 @@ func resourcePulsarSinkRead(ctx context.Context, d *schema.ResourceData, meta in
 		}
 	}
 
+	state, err := readLifecycleState(sinkLifecycleClient(meta, tenant, namespace, name),
+		d.Get(resourceSinkDesiredStateKey).(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	_ = d.Set(resourceSinkDesiredStateKey, state)
+
 	return nil
 }
 
 func resourcePulsarSinkUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := getV3ClientFromMeta(meta).Sinks()
 
-	sinkConfig, err := marshalSinkConfig(d)
-	if err != nil {
-		return diag.FromErr(err)
-	}
+	if d.HasChangesExcept(resourceSinkDesiredStateKey, resourceSinkRestartTriggerKey,
+		resourceSinkWaitForRunningKey) {
+		sinkConfig, err := marshalSinkConfig(d)
+		if err != nil {
+			return diag.FromErr(err)
+		}
 
-	updateOptions := utils.NewUpdateOptions()
-	if isPackageURLSupported(sinkConfig.Archive) {
-		err = client.UpdateSinkWithURL(sinkConfig, sinkConfig.Archive, updateOptions)
-	} else {
-		err = client.UpdateSink(sinkConfig, sinkConfig.Archive, updateOptions)
+		archiveSum, err := archiveSHA256(sinkConfig.Archive)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("ERROR_READ_ARCHIVE: %w", err))
+		}
+
+		updateOptions := utils.NewUpdateOptions()
+		if isPackageURLSupported(sinkConfig.Archive) {
+			err = client.UpdateSinkWithURL(sinkConfig, sinkConfig.Archive, updateOptions)
+		} else {
+			err = client.UpdateSink(sinkConfig, sinkConfig.Archive, updateOptions)
+		}
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		_ = d.Set(resourceSinkArchiveSHA256Key, archiveSum)
 	}
+
+	return applySinkLifecycle(ctx, d, meta)
+}
+
+// applySinkLifecycle brings the sink instances to the desired state and reads the sink back
+func applySinkLifecycle(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tenant := d.Get(resourceSinkTenantKey).(string)
+	namespace := d.Get(resourceSinkNamespaceKey).(string)
+	name := d.Get(resourceSinkNameKey).(string)
+
+	lifecycle := sinkLifecycleClient(meta, tenant, namespace, name)
+
+	err := applyLifecycle(d, lifecycle, resourceSinkDesiredStateKey, resourceSinkRestartTriggerKey)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
+	desiredState := d.Get(resourceSinkDesiredStateKey).(string)
+	if desiredState == DesiredStateRunning && d.Get(resourceSinkWaitForRunningKey).(bool) {
+		timeout := d.Timeout(schema.TimeoutUpdate)
+		if d.IsNewResource() {
+			timeout = d.Timeout(schema.TimeoutCreate)
+		}
+		err = waitForRunning(ctx, lifecycle, d.Get(resourceSinkParallelismKey).(int), timeout)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	return resourcePulsarSinkRead(ctx, d, meta)
 }
 
+func sinkLifecycleClient(meta interface{}, tenant, namespace, name string) lifecycleClient {
+	client := getV3ClientFromMeta(meta).Sinks()
+
+	return lifecycleClient{
+		start:   func() error { return client.StartSink(tenant, namespace, name) },
+		stop:    func() error { return client.StopSink(tenant, namespace, name) },
+		restart: func() error { return client.RestartSink(tenant, namespace, name) },
+		status: func() (*instancesStatus, error) {
+			status, err := client.GetSinkStatus(tenant, namespace, name)
+			if err != nil {
+				return nil, err
+			}
+
+			result := &instancesStatus{numInstances: status.NumInstances, numRunning: status.NumRunning}
+			for _, instance := range status.Instances {
+				result.addInstance(instance.Status.Running, instance.Status.Err, instance.Status.NumRestarts,
+					instance.Status.LatestSourceExceptions, instance.Status.LatestSystemExceptions)
+			}
+			return result, nil
+		},
+	}
+}
+
 func resourcePulsarSinkDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := getV3ClientFromMeta(meta).Sinks()
 
@@ -750,17 +888,11 @@ func marshalSinkConfig(d *schema.ResourceData) (*utils.SinkConfig, error) {
 
 	sinkConfig.Resources = resources
 
-	if inter, ok := d.GetOk(resourceSinkConfigsKey); ok {
-		var configs map[string]interface{}
-		configsJSON := inter.(string)
-
-		err := json.Unmarshal([]byte(configsJSON), &configs)
-		if err != nil {
-			return nil, errors.Wrapf(err, "cannot unmarshal the configs: %s", configsJSON)
-		}
-
-		sinkConfig.Configs = configs
+	configs, err := marshalConnectorConfigs(d, resourceSinkConnectorConfigsKeys)
+	if err != nil {
+		return nil, err
 	}
+	sinkConfig.Configs = configs
 
 	if inter, ok := d.GetOk(resourceSinkAutoACKKey); ok {
 		sinkConfig.AutoAck = inter.(bool)
@@ -788,7 +920,7 @@ func marshalSinkConfig(d *schema.ResourceData) (*utils.SinkConfig, error) {
 	}
 
 	if inter, ok := d.GetOk(resourceSinkRetainKeyOrderingKey); ok {
-		sinkConfig.RetainOrdering = inter.(bool)
+		sinkConfig.RetainKeyOrdering = inter.(bool)
 	}
 
 	if inter, ok := d.GetOk(resourceSinkSinkTypeKey); ok {
@@ -807,5 +939,9 @@ func marshalSinkConfig(d *schema.ResourceData) (*utils.SinkConfig, error) {
 		sinkConfig.Secrets = secrets
 	}
 
+	if inter, ok := d.GetOk(resourceSinkSecretKey); ok {
+		sinkConfig.Secrets = marshalSecret(inter.(*schema.Set))
+	}
+
 	return sinkConfig, nil
 }
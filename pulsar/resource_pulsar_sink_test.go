@@ -21,13 +21,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"regexp"
 	"strings"
 	"testing"
 
 	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/admin/config"
 	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/rest"
 	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/utils"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/pkg/errors"
@@ -40,6 +40,20 @@ var testdataArchive = "https://www.apache.org/dyn/mirrors/mirrors.cgi?" +
 
 func init() {
 	initTestWebServiceURL()
+
+	resource.AddTestSweepers("pulsar_sink", &resource.Sweeper{
+		Name: "pulsar_sink",
+		F:    testSweepSinks,
+	})
+}
+
+func testSweepSinks(url string) error {
+	client, err := sharedClientWithVersion(url, config.V3)
+	if err != nil {
+		return fmt.Errorf("ERROR_GETTING_PULSAR_CLIENT: %w", err)
+	}
+
+	return sweepInstances(url, "SINK", client.Sinks().ListSinks, client.Sinks().DeleteSink)
 }
 
 func TestSink(t *testing.T) {
@@ -114,7 +128,7 @@ func testPulsarSinkDestroy(s *terraform.State) error {
 }
 
 func TestImportExistingSink(t *testing.T) {
-	sinkName := acctest.RandString(6)
+	sinkName := testAccRandomName()
 	err := createSampleSink(sinkName)
 	if err != nil {
 		t.Fatal(err)
@@ -181,7 +195,7 @@ func createSampleSink(name string) error {
 		Archive:                    testdataArchive,
 		ProcessingGuarantees:       "EFFECTIVELY_ONCE",
 		SourceSubscriptionPosition: "Latest",
-		Inputs:                     []string{"sink-1-topic"},
+		Inputs:                     []string{"persistent://public/default/sink-1-topic"},
 		Configs:                    configs,
 		Resources: &utils.Resources{
 			CPU:  1,
@@ -189,7 +203,7 @@ func createSampleSink(name string) error {
 			RAM:  int64(bytesize.FormMegaBytes(2048).ToBytes()),
 		},
 		Secrets:                      secret,
-		DeadLetterTopic:              "dl-topic",
+		DeadLetterTopic:              "persistent://public/default/dl-topic",
 		MaxMessageRetries:            5,
 		NegativeAckRedeliveryDelayMs: 3000,
 		RetainKeyOrdering:            false,
@@ -211,13 +225,13 @@ resource "pulsar_sink" "test" {
   name = "%s"
   tenant = "public"
   namespace = "default"
-  inputs = ["sink-1-topic"]
+  inputs = ["persistent://public/default/sink-1-topic"]
   subscription_position = "Latest"
   cleanup_subscription = false
   parallelism = 1
   auto_ack = true
 
-  dead_letter_topic = "dl-topic"
+  dead_letter_topic = "persistent://public/default/dl-topic"
   max_redeliver_count = 5
   negative_ack_redelivery_delay_ms = 3000
   retain_key_ordering = false 
@@ -282,3 +296,256 @@ func TestSinkUpdate(t *testing.T) {
 		},
 	})
 }
+
+func TestSinkWithSensitiveConfigs(t *testing.T) {
+	resourceName := "pulsar_sink.sensitive"
+	name := testAccRandomName()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testPulsarSinkDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testPulsarSinkWithSensitiveConfigs(testWebServiceURL, name, "userName"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("ERROR_DUPLICATE_CONFIG_KEY: userName"),
+			},
+			{
+				Config: testPulsarSinkWithSensitiveConfigs(testWebServiceURL, name, "password"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "configs_map.userName", "postgres"),
+					resource.TestCheckResourceAttr(resourceName, "sensitive_configs.password", "password"),
+					resource.TestCheckResourceAttr(resourceName, "configs", ""),
+					func(s *terraform.State) error {
+						sinkConfig, err := getV3ClientFromMeta(testAccProvider.Meta()).Sinks().
+							GetSink("public", "default", name)
+						if err != nil {
+							return err
+						}
+						if sinkConfig.Configs["password"] != "password" {
+							return fmt.Errorf("the sensitive configs are not merged into the configs: %v",
+								sinkConfig.Configs)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config:             testPulsarSinkWithSensitiveConfigs(testWebServiceURL, name, "password"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: false,
+			},
+		},
+	})
+}
+
+func testPulsarSinkWithSensitiveConfigs(url, name, sensitiveKey string) string {
+	return fmt.Sprintf(`
+provider "pulsar" {
+  web_service_url = "%s"
+}
+
+resource "pulsar_sink" "sensitive" {
+  name                  = "%s"
+  tenant                = "public"
+  namespace             = "default"
+  inputs                = ["persistent://public/default/sink-%s-topic"]
+  subscription_position = "Latest"
+  parallelism           = 1
+  auto_ack              = true
+  archive               = "%s"
+
+  configs_map = {
+    "jdbcUrl"   = "jdbc:postgresql://localhost:5432/pulsar_postgres_jdbc_sink"
+    "tableName" = "pulsar_postgres_jdbc_sink"
+    "userName"  = "postgres"
+  }
+
+  sensitive_configs = {
+    "%s" = "password"
+  }
+}
+`, url, name, name, testdataArchive, sensitiveKey)
+}
+
+func TestSinkUnit(t *testing.T) {
+	fake := newFakeAdminServer(t)
+	resourceName := "pulsar_sink.sensitive"
+	sinkPath := "/admin/v3/sinks/public/default/jdbc"
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testUnitPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: fake.checkDestroyed("pulsar_sink", func(id string) string {
+			return "/admin/v3/sinks/" + id
+		}),
+		Steps: []resource.TestStep{
+			{
+				Config: testPulsarSinkWithSensitiveConfigs(fake.URL, "jdbc", "password"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "configs_map.%", "3"),
+					resource.TestCheckResourceAttr(resourceName, "sensitive_configs.password", "password"),
+					func(s *terraform.State) error {
+						var sinkConfig utils.SinkConfig
+						fake.get(sinkPath, &sinkConfig)
+						if sinkConfig.Configs["password"] != "password" {
+							return fmt.Errorf("expected the sensitive configs to be sent, got %v", sinkConfig.Configs)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateId:     "public/default/jdbc",
+				ImportStateVerify: true,
+				// the sensitive configs cannot be told apart from the other configs once imported
+				ImportStateVerifyIgnore: []string{"configs", "configs_map", "sensitive_configs", "wait_for_running"},
+			},
+			{
+				PreConfig: func() {
+					var sinkConfig map[string]interface{}
+					fake.get(sinkPath, &sinkConfig)
+					sinkConfig["parallelism"] = 3
+					fake.put(sinkPath, sinkConfig)
+				},
+				Config:             testPulsarSinkWithSensitiveConfigs(fake.URL, "jdbc", "password"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestSinkConfigsUnit(t *testing.T) {
+	fake := newFakeAdminServer(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testUnitPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: fake.checkDestroyed("pulsar_sink", func(id string) string {
+			return "/admin/v3/sinks/" + id
+		}),
+		Steps: []resource.TestStep{
+			{
+				Config: testPulsarSinkConfigs(fake.URL, `configs = "{\"tableName\":\"orders\"}"`),
+				Check:  resource.TestCheckResourceAttr("pulsar_sink.test", "configs", `{"tableName":"orders"}`),
+			},
+			{
+				// the configs are imported into the JSON string
+				ResourceName:            "pulsar_sink.test",
+				ImportState:             true,
+				ImportStateId:           "public/default/jdbc",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"wait_for_running"},
+			},
+			{
+				// removing the configs is planned
+				Config:             testPulsarSinkConfigs(fake.URL, ""),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testPulsarSinkConfigs(fake.URL, `sensitive_configs = { "password" = "secret" }`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("pulsar_sink.test", "configs", ""),
+					resource.TestCheckResourceAttr("pulsar_sink.test", "configs_map.%", "0"),
+				),
+			},
+			{
+				// the refresh keeps both configs empty when only the sensitive configs are set
+				Config:             testPulsarSinkConfigs(fake.URL, `sensitive_configs = { "password" = "secret" }`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: false,
+			},
+		},
+	})
+}
+
+func testPulsarSinkConfigs(url, configs string) string {
+	return testFakeProvider(url) + fmt.Sprintf(`
+resource "pulsar_sink" "test" {
+  name      = "jdbc"
+  tenant    = "public"
+  namespace = "default"
+  archive   = "%s"
+  inputs    = ["persistent://public/default/sink-topic"]
+  %s
+}
+`, testdataArchive, configs)
+}
+
+func TestSinkValidationUnit(t *testing.T) {
+	fake := newFakeAdminServer(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testUnitPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testPulsarSinkValidation(fake.URL, ""),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("ERROR_MISSING_INPUTS"),
+			},
+			{
+				Config:      testPulsarSinkValidation(fake.URL, `inputs = ["sink-topic"]`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("ERROR_INVALID_TOPIC_NAME: inputs"),
+			},
+			{
+				// retain_ordering defaults to true
+				Config: testPulsarSinkValidation(fake.URL, `
+  inputs              = ["persistent://public/default/sink-topic"]
+  retain_key_ordering = true`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("ERROR_INCOMPATIBLE_OPTIONS: retain_ordering"),
+			},
+			{
+				Config: testPulsarSinkValidation(fake.URL, `
+  inputs                = ["persistent://public/default/sink-topic"]
+  processing_guarantees = "EFFECTIVELY_ONCE"
+  retain_ordering       = false
+  retain_key_ordering   = true`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("ERROR_INCOMPATIBLE_OPTIONS: retain_key_ordering"),
+			},
+			{
+				Config: testPulsarSinkValidation(fake.URL, `
+  inputs            = ["persistent://public/default/sink-topic"]
+  dead_letter_topic = "persistent://public/default/sink-dlq"`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("ERROR_INCOMPATIBLE_OPTIONS: dead_letter_topic"),
+			},
+		},
+	})
+}
+
+func testPulsarSinkValidation(url, attributes string) string {
+	return testFakeProvider(url) + fmt.Sprintf(`
+resource "pulsar_sink" "test" {
+  name      = "jdbc"
+  tenant    = "public"
+  namespace = "default"
+  archive   = "%s"
+  configs   = "{}"
+  %s
+}
+`, testdataArchive, attributes)
+}
+
+func TestSinkRetainKeyOrdering(t *testing.T) {
+	d := resourcePulsarSink().TestResourceData()
+	if err := d.Set(resourceSinkRetainKeyOrderingKey, true); err != nil {
+		t.Fatal(err)
+	}
+	sinkConfig, err := marshalSinkConfig(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sinkConfig.RetainKeyOrdering || sinkConfig.RetainOrdering {
+		t.Fatalf("expected retain_key_ordering to only set RetainKeyOrdering, got RetainKeyOrdering %t and RetainOrdering %t",
+			sinkConfig.RetainKeyOrdering, sinkConfig.RetainOrdering)
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/utils"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/pkg/errors"
 
@@ -47,65 +48,88 @@ const (
 	resourceSourceRAMKey                      = "ram_mb"
 	resourceSourceDiskKey                     = "disk_mb"
 	resourceSourceConfigsKey                  = "configs"
+	resourceSourceConfigsMapKey               = "configs_map"
+	resourceSourceSensitiveConfigsKey         = "sensitive_configs"
 	resourceSourceRuntimeFlagsKey             = "runtime_flags"
 	resourceSourceCustomRuntimeOptionsKey     = "custom_runtime_options"
 	resourceSourceSchemaTypeKey               = "schema_type"
 	resourceSourceSecretsKey                  = "secrets"
-	// producer config
-	resourceSourcePCMaxPendingMsgKey                = "max_pending_messages"
-	resourceSourcePCMaxPendingMsgAcrossPartitionKey = "max_pending_messages_across_partitions"
-	resourceSourcePCUseThreadLocalProducersKey      = "use_thread_local_producers"
-	resourceSourcePCBatchBuilderKey                 = "batch_builder"
-	resourceSourcePCCompressionTypeKey              = "compression_type"
-	// producer crypto config
-	resourceSourcePCCryptoKeyReaderClassNameKey     = "crypto_key_reader_classname"
-	resourceSourcePCCryptoKeyReaderConfigKey        = "crypto_key_reader_config"
-	resourceSourcePCEncryptionKeysKey               = "encryption_keys"
-	resourceSourcePCProducerCryptoFailureActionKey  = "producer_crypto_failure_action"
-	resourceSourcePCSConsumerCryptoFailureActionKey = "consumer_crypto_failure_action"
+	resourceSourceArchiveSHA256Key            = "archive_sha256"
+	resourceSourceDesiredStateKey             = "desired_state"
+	resourceSourceRestartTriggerKey           = "restart_trigger"
+	resourceSourceSecretKey                   = "secret"
+	resourceSourceWaitForRunningKey           = "wait_for_running"
+	// batch source config
+	resourceSourceBatchSourceConfigKey           = "batch_source_config"
+	resourceSourceDiscoveryTriggererClassnameKey = "discovery_triggerer_classname"
+	resourceSourceDiscoveryTriggererConfigKey    = "discovery_triggerer_config"
 )
 
 var resourceSourceDescriptions = make(map[string]string)
 
+var resourceSourceConnectorConfigsKeys = connectorConfigsKeys{
+	configs:          resourceSourceConfigsKey,
+	configsMap:       resourceSourceConfigsMapKey,
+	sensitiveConfigs: resourceSourceSensitiveConfigsKey,
+}
+
+var resourceSourceInstanceConfigKeys = instanceConfigKeys{
+	topics: []string{resourceSourceDestinationTopicNamesKey},
+}
+
 func init() {
 	//nolint:lll
 	resourceSourceDescriptions = map[string]string{
-		resourceSourceTenantKey:                         "The source's tenant",
-		resourceSourceNamespaceKey:                      "The source's namespace",
-		resourceSourceNameKey:                           "The source's name",
-		resourceSourceArchiveKey:                        "The path to the NAR archive for the Source. It also supports url-path [http/https/file (file protocol assumes that file already exists on worker host)] from which worker can download the package",
-		resourceSourceProcessingGuaranteesKey:           "Define the message delivery semantics, default to ATLEAST_ONCE (ATLEAST_ONCE, ATMOST_ONCE, EFFECTIVELY_ONCE)",
-		resourceSourceDestinationTopicNamesKey:          "The Pulsar topic to which data is sent",
-		resourceSourceDeserializationClassnameKey:       "The SerDe classname for the source",
-		resourceSourceParallelismKey:                    "The source's parallelism factor",
-		resourceSourceClassnameKey:                      "The source's class name if archive is file-url-path (file://)",
-		resourceSourceCPUKey:                            "The CPU that needs to be allocated per source instance (applicable only to Docker runtime)",
-		resourceSourceRAMKey:                            "The RAM that need to be allocated per source instance (applicable only to the process and Docker runtimes)",
-		resourceSourceDiskKey:                           "The disk that need to be allocated per source instance (applicable only to Docker runtime)",
-		resourceSourceConfigsKey:                        "User defined configs key/values (JSON string)",
-		resourceSourceRuntimeFlagsKey:                   "User defined configs key/values (JSON string)",
-		resourceSourceCustomRuntimeOptionsKey:           "A string that encodes options to customize the runtime, see docs for configured runtime for details",
-		resourceSourceSchemaTypeKey:                     "The schema type (either a builtin schema like 'avro', 'json', etc.. or custom Schema class name to be used to encode messages emitted from the source",
-		resourceSourceSecretsKey:                        "The map of secretName to an object that encapsulates how the secret is fetched by the underlying secrets provider",
-		resourceSourcePCMaxPendingMsgKey:                "The maximum size of a queue holding pending messages",
-		resourceSourcePCMaxPendingMsgAcrossPartitionKey: "The maximum number of pending messages across partitions",
-		resourceSourcePCUseThreadLocalProducersKey:      "Whether to use thread local producers",
-		resourceSourcePCBatchBuilderKey:                 "BatchBuilder provides two types of batch construction methods, DEFAULT and KEY_BASED.",
-		resourceSourcePCCompressionTypeKey:              "Set the compression type for the producer. By default, message payloads are not compressed. Supported compression types are: LZ4, ZLIB, ZSTD, SNAPPY and NONE",
-		resourceSourcePCCryptoKeyReaderClassNameKey:     "The classname for the crypto key reader that can be used to access the keys in the keystore",
-		resourceSourcePCCryptoKeyReaderConfigKey:        "The config for the crypto key reader that can be used to access the keys in the keystore",
-		resourceSourcePCEncryptionKeysKey:               "One or more public keys to encrypt data key. It can be used to encrypt data key with multiple keys.",
-		resourceSourcePCProducerCryptoFailureActionKey:  "The desired action if producer fail to encrypt data, one of FAIL, SEND",
-		resourceSourcePCSConsumerCryptoFailureActionKey: "The desired action if consumer fail to decrypt data, one of FAIL, DISCARD, CONSUME",
+		resourceSourceTenantKey:                   "The source's tenant",
+		resourceSourceNamespaceKey:                "The source's namespace",
+		resourceSourceNameKey:                     "The source's name",
+		resourceSourceArchiveKey:                  "The path to the NAR archive for the Source. It also supports url-path [http/https/file (file protocol assumes that file already exists on worker host)] from which worker can download the package",
+		resourceSourceProcessingGuaranteesKey:     "Define the message delivery semantics, default to ATLEAST_ONCE (ATLEAST_ONCE, ATMOST_ONCE, EFFECTIVELY_ONCE)",
+		resourceSourceDestinationTopicNamesKey:    "The Pulsar topic to which data is sent",
+		resourceSourceDeserializationClassnameKey: "The SerDe classname for the source",
+		resourceSourceParallelismKey:              "The source's parallelism factor",
+		resourceSourceClassnameKey:                "The source's class name if archive is file-url-path (file://)",
+		resourceSourceCPUKey:                      "The CPU that needs to be allocated per source instance (applicable only to Docker runtime)",
+		resourceSourceRAMKey:                      "The RAM that need to be allocated per source instance (applicable only to the process and Docker runtimes)",
+		resourceSourceDiskKey:                     "The disk that need to be allocated per source instance (applicable only to Docker runtime)",
+		resourceSourceConfigsKey:                  "User defined configs key/values (JSON string), conflicts with `configs_map`",
+		resourceSourceConfigsMapKey:               "User defined configs key/values as a map of strings, conflicts with `configs`",
+		resourceSourceSensitiveConfigsKey:         "Sensitive configs key/values, e.g. credentials, merged into the configs on apply and never shown in plans nor read back from the source",
+		resourceSourceRuntimeFlagsKey:             "User defined configs key/values (JSON string)",
+		resourceSourceCustomRuntimeOptionsKey:     "A string that encodes options to customize the runtime, see docs for configured runtime for details",
+		resourceSourceSchemaTypeKey:               "The schema type (either a builtin schema like 'avro', 'json', etc.. or custom Schema class name to be used to encode messages emitted from the source",
+		resourceSourceSecretsKey:                  "The map of secretName to an object that encapsulates how the secret is fetched by the underlying secrets provider",
+		resourceSourceArchiveSHA256Key:            "The sha256 of the local archive, used to detect content changes when the archive path stays the same.",
+		resourceSourceDesiredStateKey:             "The desired state of the source instances. Possible values are `running` and `stopped`, the actual state is read from the instance status.",
+		resourceSourceRestartTriggerKey:           "Arbitrary key/values that restart the source instances when changed.",
+		resourceSourceSecretKey:                   "The secrets of the source fetched by the secrets provider, conflicts with `secrets`.",
+		resourceSourceWaitForRunningKey:           "Whether to wait on create and update until all the source instances are running, the apply fails with the last instance error when an instance restarts or raises an exception, or when they do not start within the timeout.",
+
+		// batch source config
+		resourceSourceBatchSourceConfigKey:           "The configuration of a batch source, the source is replaced when it is added, removed or its discovery triggerer class changes",
+		resourceSourceDiscoveryTriggererClassnameKey: "The class name of the discovery triggerer, which triggers the discovery of new tasks, e.g. a cron triggerer",
+		resourceSourceDiscoveryTriggererConfigKey:    "The config of the discovery triggerer (JSON string)",
 	}
 }
 
 func resourcePulsarSource() *schema.Resource {
-	return &schema.Resource{
+	resource := &schema.Resource{
 		CreateContext: resourcePulsarSourceCreate,
 		ReadContext:   resourcePulsarSourceRead,
 		UpdateContext: resourcePulsarSourceUpdate,
 		DeleteContext: resourcePulsarSourceDelete,
+		Timeouts:      lifecycleTimeouts(),
+		CustomizeDiff: customdiff.All(
+			customizeDiffArchiveSHA256(resourceSourceArchiveSHA256Key, resourceSourceArchiveKey),
+			customizeDiffSecret(resourceSourceSecretKey),
+			customizeDiffConnectorConfigs(resourceSourceConnectorConfigsKeys),
+			customizeDiffInstanceConfig(resourceSourceInstanceConfigKeys),
+			// the worker cannot turn a streaming source into a batch source and vice versa
+			customdiff.ForceNewIfChange(resourceSourceBatchSourceConfigKey, func(ctx context.Context, old, new,
+				meta interface{}) bool {
+				return len(old.([]interface{})) != len(new.([]interface{}))
+			}),
+		),
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 				id := d.Id()
@@ -123,6 +147,15 @@ func resourcePulsarSource() *schema.Resource {
 				if diags.HasError() {
 					return nil, fmt.Errorf("import %q: %s", d.Id(), diags[0].Summary)
 				}
+
+				// read all the configs into the JSON string since none of them is configured yet
+				config, err := getV3ClientFromMeta(meta).Sources().GetSource(parts[0], parts[1], parts[2])
+				if err != nil {
+					return nil, fmt.Errorf("import %q: %w", d.Id(), err)
+				}
+				if err := importConnectorConfigs(d, resourceSourceConnectorConfigsKeys, config.Configs); err != nil {
+					return nil, fmt.Errorf("import %q: %w", d.Id(), err)
+				}
 				return []*schema.ResourceData{d}, nil
 			},
 		},
@@ -217,12 +250,6 @@ func resourcePulsarSource() *schema.Resource {
 				Computed:    true,
 				Description: resourceSourceDescriptions[resourceSourceDiskKey],
 			},
-			resourceSourceConfigsKey: {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Computed:    true,
-				Description: resourceSourceDescriptions[resourceSourceConfigsKey],
-			},
 			resourceSourceRuntimeFlagsKey: {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -240,67 +267,57 @@ func resourcePulsarSource() *schema.Resource {
 				Description: resourceSourceDescriptions[resourceSourceSchemaTypeKey],
 			},
 			resourceSourceSecretsKey: {
-				Type:         schema.TypeString,
-				Optional:     true,
-				Description:  resourceSourceDescriptions[resourceSourceSecretsKey],
-				ValidateFunc: jsonValidateFunc,
-			},
-			resourceSourcePCMaxPendingMsgKey: {
-				Type:        schema.TypeInt,
-				Optional:    true,
-				Description: resourceSourceDescriptions[resourceSourcePCMaxPendingMsgKey],
-			},
-			resourceSourcePCMaxPendingMsgAcrossPartitionKey: {
-				Type:        schema.TypeInt,
-				Optional:    true,
-				Description: resourceSourceDescriptions[resourceSourcePCMaxPendingMsgAcrossPartitionKey],
-			},
-			resourceSourcePCUseThreadLocalProducersKey: {
-				Type:        schema.TypeBool,
-				Optional:    true,
-				Description: resourceSourceDescriptions[resourceSourcePCUseThreadLocalProducersKey],
-			},
-			resourceSourcePCBatchBuilderKey: {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: resourceSourceDescriptions[resourceSourcePCBatchBuilderKey],
-			},
-			resourceSourcePCCompressionTypeKey: {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: resourceSourceDescriptions[resourceSourcePCCompressionTypeKey],
-			},
-			resourceSourcePCCryptoKeyReaderClassNameKey: {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: resourceSourceDescriptions[resourceSourcePCCryptoKeyReaderClassNameKey],
-			},
-			resourceSourcePCCryptoKeyReaderConfigKey: {
-				Type:         schema.TypeString,
-				Optional:     true,
-				Description:  resourceSourceDescriptions[resourceSourcePCCryptoKeyReaderConfigKey],
-				ValidateFunc: jsonValidateFunc,
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{resourceSourceSecretKey},
+				Description:   resourceSourceDescriptions[resourceSourceSecretsKey],
+				ValidateFunc:  jsonValidateFunc,
 			},
-			resourceSourcePCEncryptionKeysKey: {
-				Type:        schema.TypeSet,
+			resourceSourceSecretKey: schemaSecret(resourceSourceDescriptions[resourceSourceSecretKey], resourceSourceSecretsKey),
+			resourceSourceBatchSourceConfigKey: {
+				Type:        schema.TypeList,
 				Optional:    true,
-				Description: resourceSourceDescriptions[resourceSourcePCEncryptionKeysKey],
-				Elem: &schema.Schema{
-					Type: schema.TypeString,
+				MaxItems:    1,
+				Description: resourceSourceDescriptions[resourceSourceBatchSourceConfigKey],
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						resourceSourceDiscoveryTriggererClassnameKey: {
+							Type:         schema.TypeString,
+							Required:     true,
+							ForceNew:     true,
+							Description:  resourceSourceDescriptions[resourceSourceDiscoveryTriggererClassnameKey],
+							ValidateFunc: validateNotBlank,
+						},
+						resourceSourceDiscoveryTriggererConfigKey: {
+							Type:             schema.TypeString,
+							Optional:         true,
+							Description:      resourceSourceDescriptions[resourceSourceDiscoveryTriggererConfigKey],
+							ValidateFunc:     jsonValidateFunc,
+							DiffSuppressFunc: jsonDiffSuppressFunc,
+						},
+					},
 				},
 			},
-			resourceSourcePCProducerCryptoFailureActionKey: {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: resourceSourceDescriptions[resourceSourcePCProducerCryptoFailureActionKey],
-			},
-			resourceSourcePCSConsumerCryptoFailureActionKey: {
+			resourceSourceArchiveSHA256Key: {
 				Type:        schema.TypeString,
-				Optional:    true,
-				Description: resourceSourceDescriptions[resourceSourcePCSConsumerCryptoFailureActionKey],
+				Computed:    true,
+				Description: resourceSourceDescriptions[resourceSourceArchiveSHA256Key],
 			},
+			resourceSourceDesiredStateKey:   schemaDesiredState(resourceSourceDescriptions[resourceSourceDesiredStateKey]),
+			resourceSourceRestartTriggerKey: schemaRestartTrigger(resourceSourceDescriptions[resourceSourceRestartTriggerKey]),
+			resourceSourceWaitForRunningKey: schemaWaitForRunning(resourceSourceDescriptions[resourceSourceWaitForRunningKey]),
 		},
 	}
+
+	// the producer config is flattened into the source
+	for key, value := range schemaProducerConfig() {
+		resource.Schema[key] = value
+	}
+	for key, value := range schemaConnectorConfigs(resourceSourceConnectorConfigsKeys, resourceSourceDescriptions) {
+		resource.Schema[key] = value
+	}
+
+	return resource
 }
 
 func resourcePulsarSourceCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -311,6 +328,11 @@ func resourcePulsarSourceCreate(ctx context.Context, d *schema.ResourceData, met
 		return diag.FromErr(err)
 	}
 
+	archiveSum, err := archiveSHA256(sourceConfig.Archive)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("ERROR_READ_ARCHIVE: %w", err))
+	}
+
 	if isPackageURLSupported(sourceConfig.Archive) {
 		err = client.CreateSourceWithURL(sourceConfig, sourceConfig.Archive)
 	} else {
@@ -322,7 +344,11 @@ func resourcePulsarSourceCreate(ctx context.Context, d *schema.ResourceData, met
 	}
 	tflog.Debug(ctx, "@@@Create source complete")
 
-	return resourcePulsarSourceRead(ctx, d, meta)
+	// the source exists from now on, a failed wait taints it instead of leaving it out of the state
+	d.SetId(fmt.Sprintf("%s/%s/%s", sourceConfig.Tenant, sourceConfig.Namespace, sourceConfig.Name))
+	_ = d.Set(resourceSourceArchiveSHA256Key, archiveSum)
+
+	return applySourceLifecycle(ctx, d, meta)
 }
 
 func resourcePulsarSourceRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -394,16 +420,9 @@ func resourcePulsarSourceRead(ctx context.Context, d *schema.ResourceData, meta
 		}
 	}
 
-	if len(sourceConfig.Configs) != 0 {
-		b, err := json.Marshal(sourceConfig.Configs)
-		if err != nil {
-			return diag.FromErr(errors.Wrap(err, "cannot marshal configs from sourceConfig"))
-		}
-
-		err = d.Set(resourceSourceConfigsKey, string(b))
-		if err != nil {
-			return diag.FromErr(err)
-		}
+	err = flattenConnectorConfigs(d, resourceSourceConnectorConfigsKeys, sourceConfig.Configs)
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
 	if len(sourceConfig.RuntimeFlags) != 0 {
@@ -435,117 +454,151 @@ func resourcePulsarSourceRead(ctx context.Context, d *schema.ResourceData, meta
 	}
 
 	if len(sourceConfig.Secrets) != 0 {
-		s, err := json.Marshal(sourceConfig.Secrets)
-		if err != nil {
-			return diag.FromErr(errors.Wrap(err, "cannot marshal secrets from sourceConfig"))
-		}
-		err = d.Set(resourceSourceSecretsKey, string(s))
-		if err != nil {
-			return diag.FromErr(err)
-		}
-	}
-
-	if sourceConfig.ProducerConfig != nil {
-		if sourceConfig.ProducerConfig.MaxPendingMessages > 0 {
-			err = d.Set(resourceSourcePCMaxPendingMsgKey, sourceConfig.ProducerConfig.MaxPendingMessages)
+		secret, structured := flattenSecret(sourceConfig.Secrets)
+		if _, ok := d.GetOk(resourceSourceSecretsKey); !ok && structured {
+			err = d.Set(resourceSourceSecretKey, secret)
 			if err != nil {
 				return diag.FromErr(err)
 			}
-		}
-
-		if sourceConfig.ProducerConfig.MaxPendingMessagesAcrossPartitions > 0 {
-			err = d.Set(resourceSourcePCMaxPendingMsgAcrossPartitionKey,
-				sourceConfig.ProducerConfig.MaxPendingMessagesAcrossPartitions)
+		} else {
+			s, err := json.Marshal(sourceConfig.Secrets)
+			if err != nil {
+				return diag.FromErr(errors.Wrap(err, "cannot marshal secrets from sourceConfig"))
+			}
+			err = d.Set(resourceSourceSecretsKey, string(s))
 			if err != nil {
 				return diag.FromErr(err)
 			}
 		}
+	}
 
-		err = d.Set(resourceSourcePCUseThreadLocalProducersKey, sourceConfig.ProducerConfig.UseThreadLocalProducers)
-		if err != nil {
-			return diag.FromErr(err)
+	if sourceConfig.BatchSourceConfig != nil {
+		batchSourceConfig := map[string]interface{}{
+			resourceSourceDiscoveryTriggererClassnameKey: sourceConfig.BatchSourceConfig.DiscoveryTriggererClassName,
 		}
 
-		if len(sourceConfig.ProducerConfig.BatchBuilder) != 0 {
-			err = d.Set(resourceSourcePCBatchBuilderKey, sourceConfig.ProducerConfig.BatchBuilder)
+		if len(sourceConfig.BatchSourceConfig.DiscoveryTriggererConfig) != 0 {
+			b, err := json.Marshal(sourceConfig.BatchSourceConfig.DiscoveryTriggererConfig)
 			if err != nil {
-				return diag.FromErr(err)
+				return diag.FromErr(errors.Wrap(err, "cannot marshal discovery triggerer config from sourceConfig"))
 			}
+			batchSourceConfig[resourceSourceDiscoveryTriggererConfigKey] = string(b)
+		}
+
+		err = d.Set(resourceSourceBatchSourceConfigKey, []interface{}{batchSourceConfig})
+	} else {
+		err = d.Set(resourceSourceBatchSourceConfigKey, nil)
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if sourceConfig.ProducerConfig != nil {
+		producerConfig, err := flattenProducerConfig(sourceConfig.ProducerConfig)
+		if err != nil {
+			return diag.FromErr(err)
 		}
 
-		if len(sourceConfig.ProducerConfig.CompressionType) != 0 {
-			err = d.Set(resourceSourcePCCompressionTypeKey, sourceConfig.ProducerConfig.CompressionType)
+		for key, value := range producerConfig {
+			err = d.Set(key, value)
 			if err != nil {
 				return diag.FromErr(err)
 			}
 		}
+	}
 
-		if sourceConfig.ProducerConfig.CryptoConfig != nil {
-			cryptoConfig := sourceConfig.ProducerConfig.CryptoConfig
+	state, err := readLifecycleState(sourceLifecycleClient(meta, tenant, namespace, name),
+		d.Get(resourceSourceDesiredStateKey).(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	_ = d.Set(resourceSourceDesiredStateKey, state)
 
-			err = d.Set(resourceSourcePCCryptoKeyReaderClassNameKey, cryptoConfig.CryptoKeyReaderClassName)
-			if err != nil {
-				return diag.FromErr(err)
-			}
+	return nil
+}
 
-			if len(cryptoConfig.CryptoKeyReaderConfig) != 0 {
-				c, err := json.Marshal(cryptoConfig.CryptoKeyReaderConfig)
-				if err != nil {
-					return diag.FromErr(errors.Wrap(err, "cannot marshal config from crypto key reader"))
-				}
-				err = d.Set(resourceSourcePCCryptoKeyReaderConfigKey, string(c))
-				if err != nil {
-					return diag.FromErr(err)
-				}
-			}
+func resourcePulsarSourceUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := getV3ClientFromMeta(meta).Sources()
 
-			if len(cryptoConfig.EncryptionKeys) != 0 {
-				err = d.Set(resourceSourcePCEncryptionKeysKey, cryptoConfig.EncryptionKeys)
-				if err != nil {
-					return diag.FromErr(err)
-				}
-			}
+	if d.HasChangesExcept(resourceSourceDesiredStateKey, resourceSourceRestartTriggerKey,
+		resourceSourceWaitForRunningKey) {
+		sourceConfig, err := marshalSourceConfig(d)
+		if err != nil {
+			return diag.FromErr(err)
+		}
 
-			if len(cryptoConfig.ProducerCryptoFailureAction) != 0 {
-				err = d.Set(resourceSourcePCProducerCryptoFailureActionKey, cryptoConfig.ProducerCryptoFailureAction)
-				if err != nil {
-					return diag.FromErr(err)
-				}
-			}
+		archiveSum, err := archiveSHA256(sourceConfig.Archive)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("ERROR_READ_ARCHIVE: %w", err))
+		}
 
-			if len(cryptoConfig.ConsumerCryptoFailureAction) != 0 {
-				err = d.Set(resourceSourcePCSConsumerCryptoFailureActionKey, cryptoConfig.ConsumerCryptoFailureAction)
-				if err != nil {
-					return diag.FromErr(err)
-				}
-			}
+		updateOptions := utils.NewUpdateOptions()
+		if isPackageURLSupported(sourceConfig.Archive) {
+			err = client.UpdateSourceWithURL(sourceConfig, sourceConfig.Archive, updateOptions)
+		} else {
+			err = client.UpdateSource(sourceConfig, sourceConfig.Archive, updateOptions)
 		}
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		_ = d.Set(resourceSourceArchiveSHA256Key, archiveSum)
 	}
 
-	return nil
+	return applySourceLifecycle(ctx, d, meta)
 }
 
-func resourcePulsarSourceUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := getV3ClientFromMeta(meta).Sources()
+// applySourceLifecycle brings the source instances to the desired state and reads the source back
+func applySourceLifecycle(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tenant := d.Get(resourceSourceTenantKey).(string)
+	namespace := d.Get(resourceSourceNamespaceKey).(string)
+	name := d.Get(resourceSourceNameKey).(string)
 
-	sourceConfig, err := marshalSourceConfig(d)
+	lifecycle := sourceLifecycleClient(meta, tenant, namespace, name)
+
+	err := applyLifecycle(d, lifecycle, resourceSourceDesiredStateKey, resourceSourceRestartTriggerKey)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	updateOptions := utils.NewUpdateOptions()
-	if isPackageURLSupported(sourceConfig.Archive) {
-		err = client.UpdateSourceWithURL(sourceConfig, sourceConfig.Archive, updateOptions)
-	} else {
-		err = client.UpdateSource(sourceConfig, sourceConfig.Archive, updateOptions)
-	}
-	if err != nil {
-		return diag.FromErr(err)
+	desiredState := d.Get(resourceSourceDesiredStateKey).(string)
+	if desiredState == DesiredStateRunning && d.Get(resourceSourceWaitForRunningKey).(bool) {
+		timeout := d.Timeout(schema.TimeoutUpdate)
+		if d.IsNewResource() {
+			timeout = d.Timeout(schema.TimeoutCreate)
+		}
+		err = waitForRunning(ctx, lifecycle, d.Get(resourceSourceParallelismKey).(int), timeout)
+		if err != nil {
+			return diag.FromErr(err)
+		}
 	}
 
 	return resourcePulsarSourceRead(ctx, d, meta)
 }
 
+func sourceLifecycleClient(meta interface{}, tenant, namespace, name string) lifecycleClient {
+	client := getV3ClientFromMeta(meta).Sources()
+
+	return lifecycleClient{
+		start:   func() error { return client.StartSource(tenant, namespace, name) },
+		stop:    func() error { return client.StopSource(tenant, namespace, name) },
+		restart: func() error { return client.RestartSource(tenant, namespace, name) },
+		status: func() (*instancesStatus, error) {
+			status, err := client.GetSourceStatus(tenant, namespace, name)
+			if err != nil {
+				return nil, err
+			}
+
+			result := &instancesStatus{numInstances: status.NumInstances, numRunning: status.NumRunning}
+			for _, instance := range status.Instances {
+				result.addInstance(instance.Status.Running, instance.Status.Err, instance.Status.NumRestarts,
+					instance.Status.LatestSourceExceptions, instance.Status.LatestSystemExceptions)
+			}
+			return result, nil
+		},
+	}
+}
+
 func resourcePulsarSourceDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := getV3ClientFromMeta(meta).Sources()
 
@@ -615,17 +668,11 @@ func marshalSourceConfig(d *schema.ResourceData) (*utils.SourceConfig, error) {
 
 	sourceConfig.Resources = resources
 
-	if inter, ok := d.GetOk(resourceSourceConfigsKey); ok {
-		var configs map[string]interface{}
-		configsJSON := inter.(string)
-
-		err := json.Unmarshal([]byte(configsJSON), &configs)
-		if err != nil {
-			return nil, errors.Wrapf(err, "cannot unmarshal the configs: %s", configsJSON)
-		}
-
-		sourceConfig.Configs = configs
+	configs, err := marshalConnectorConfigs(d, resourceSourceConnectorConfigsKeys)
+	if err != nil {
+		return nil, err
 	}
+	sourceConfig.Configs = configs
 
 	if inter, ok := d.GetOk(resourceSourceRuntimeFlagsKey); ok {
 		sourceConfig.RuntimeFlags = inter.(string)
@@ -651,65 +698,32 @@ func marshalSourceConfig(d *schema.ResourceData) (*utils.SourceConfig, error) {
 		sourceConfig.Secrets = secrets
 	}
 
-	producerConfig := &utils.ProducerConfig{}
-	if inter, ok := d.GetOk(resourceSourcePCMaxPendingMsgKey); ok {
-		producerConfig.MaxPendingMessages = inter.(int)
+	if inter, ok := d.GetOk(resourceSourceSecretKey); ok {
+		sourceConfig.Secrets = marshalSecret(inter.(*schema.Set))
 	}
 
-	if inter, ok := d.GetOk(resourceSourcePCMaxPendingMsgAcrossPartitionKey); ok {
-		producerConfig.MaxPendingMessagesAcrossPartitions = inter.(int)
-	}
-
-	if inter, ok := d.GetOk(resourceSourcePCUseThreadLocalProducersKey); ok {
-		producerConfig.UseThreadLocalProducers = inter.(bool)
-	}
-
-	if inter, ok := d.GetOk(resourceSourcePCBatchBuilderKey); ok {
-		producerConfig.BatchBuilder = inter.(string)
-	}
-
-	if inter, ok := d.GetOk(resourceSourcePCCompressionTypeKey); ok {
-		producerConfig.CompressionType = inter.(string)
-	}
-
-	cryptoConfig := &utils.CryptoConfig{}
-	if inter, ok := d.GetOk(resourceSourcePCCryptoKeyReaderClassNameKey); ok {
-		cryptoConfig.CryptoKeyReaderClassName = inter.(string)
-	}
-
-	if inter, ok := d.GetOk(resourceSourcePCCryptoKeyReaderConfigKey); ok {
-		var cryptoKeyReaderConfig map[string]interface{}
-		cryptoKeyReaderConfigJSON := inter.(string)
-
-		err := json.Unmarshal([]byte(cryptoKeyReaderConfigJSON), &cryptoKeyReaderConfig)
-		if err != nil {
-			return nil, errors.Wrapf(err,
-				"cannot unmarshal the cryptoKeyReaderConfig: %s", cryptoKeyReaderConfigJSON)
-		}
-
-		cryptoConfig.CryptoKeyReaderConfig = cryptoKeyReaderConfig
-	}
+	if inter, ok := d.GetOk(resourceSourceBatchSourceConfigKey); ok {
+		if items := inter.([]interface{}); len(items) > 0 && items[0] != nil {
+			data := items[0].(map[string]interface{})
+			batchSourceConfig := &utils.BatchSourceConfig{
+				DiscoveryTriggererClassName: data[resourceSourceDiscoveryTriggererClassnameKey].(string),
+			}
 
-	if inter, ok := d.GetOk(resourceSourcePCEncryptionKeysKey); ok {
-		encryptionKeysSet := inter.(*schema.Set)
-		var encryptionKeys []string
+			if configJSON := data[resourceSourceDiscoveryTriggererConfigKey].(string); configJSON != "" {
+				err := json.Unmarshal([]byte(configJSON), &batchSourceConfig.DiscoveryTriggererConfig)
+				if err != nil {
+					return nil, errors.Wrapf(err, "cannot unmarshal the discovery triggerer config: %s", configJSON)
+				}
+			}
 
-		for _, item := range encryptionKeysSet.List() {
-			encryptionKeys = append(encryptionKeys, item.(string))
+			sourceConfig.BatchSourceConfig = batchSourceConfig
 		}
-
-		cryptoConfig.EncryptionKeys = encryptionKeys
-	}
-
-	if inter, ok := d.GetOk(resourceSourcePCProducerCryptoFailureActionKey); ok {
-		cryptoConfig.ProducerCryptoFailureAction = inter.(string)
 	}
 
-	if inter, ok := d.GetOk(resourceSourcePCSConsumerCryptoFailureActionKey); ok {
-		cryptoConfig.ConsumerCryptoFailureAction = inter.(string)
+	producerConfig, err := marshalProducerConfig(d.GetOk)
+	if err != nil {
+		return nil, err
 	}
-
-	producerConfig.CryptoConfig = cryptoConfig
 	sourceConfig.ProducerConfig = producerConfig
 
 	return sourceConfig, nil
@@ -21,13 +21,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"regexp"
 	"strings"
 	"testing"
 
 	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/admin/config"
 	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/rest"
 	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/utils"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/pkg/errors"
@@ -38,6 +38,20 @@ import (
 
 func init() {
 	initTestWebServiceURL()
+
+	resource.AddTestSweepers("pulsar_source", &resource.Sweeper{
+		Name: "pulsar_source",
+		F:    testSweepSources,
+	})
+}
+
+func testSweepSources(url string) error {
+	client, err := sharedClientWithVersion(url, config.V3)
+	if err != nil {
+		return fmt.Errorf("ERROR_GETTING_PULSAR_CLIENT: %w", err)
+	}
+
+	return sweepInstances(url, "SOURCE", client.Sources().ListSources, client.Sources().DeleteSource)
 }
 
 var testdataSourceArchive = "https://www.apache.org/dyn/mirrors/mirrors.cgi" +
@@ -78,7 +92,7 @@ func TestSource(t *testing.T) {
 					assert.Equal(t, "default", config.Namespace)
 					// It always empty when config.Archive not built-in URL
 					assert.Equal(t, "", config.Archive)
-					assert.Equal(t, "source-1-topic", config.TopicName)
+					assert.Equal(t, "persistent://public/default/source-1-topic", config.TopicName)
 					assert.Equal(t, ProcessingGuaranteesEffectivelyOnce, config.ProcessingGuarantees)
 					assert.Equal(t, 1, config.Parallelism)
 					assert.NotNil(t, config.Configs)
@@ -132,7 +146,7 @@ func getPulsarSourceByResourceID(id string) (*utils.SourceConfig, error) {
 }
 
 func TestImportExistingSource(t *testing.T) {
-	sourceName := acctest.RandString(6)
+	sourceName := testAccRandomName()
 	err := createSampleSource(sourceName)
 	if err != nil {
 		t.Fatal(err)
@@ -194,7 +208,7 @@ func createSampleSource(name string) error {
 		Tenant:               "public",
 		Namespace:            "default",
 		Name:                 name,
-		TopicName:            "source-1-topic",
+		TopicName:            "persistent://public/default/source-1-topic",
 		Parallelism:          1,
 		Archive:              testdataSourceArchive,
 		ProcessingGuarantees: ProcessingGuaranteesEffectivelyOnce,
@@ -234,7 +248,7 @@ resource "pulsar_source" "test" {
 
   archive = "%s"
 
-  destination_topic_name = "source-1-topic"
+  destination_topic_name = "persistent://public/default/source-1-topic"
 
   processing_guarantees = "EFFECTIVELY_ONCE"
 
@@ -255,3 +269,199 @@ resource "pulsar_source" "test" {
 }
 `, testWebServiceURL, name, testdataSourceArchive)
 }
+
+var testdataBatchSourceArchive = "https://www.apache.org/dyn/mirrors/mirrors.cgi" +
+	"?action=download&filename=pulsar/pulsar-2.10.4/connectors/pulsar-io-batch-data-generator-2.10.4.nar"
+
+func TestSourceWithBatchSourceConfig(t *testing.T) {
+	resourceName := "pulsar_source.batch"
+	name := testAccRandomName()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testPulsarSourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testPulsarBatchSource(testWebServiceURL, name, "* * * * * *"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "batch_source_config.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "batch_source_config.0.discovery_triggerer_classname",
+						"org.apache.pulsar.io.batchdiscovery.CronTriggerer"),
+					resource.TestCheckResourceAttr(resourceName, "batch_source_config.0.discovery_triggerer_config",
+						`{"__CRON__":"* * * * * *"}`),
+				),
+			},
+			{
+				Config: testPulsarBatchSource(testWebServiceURL, name, "0 * * * * *"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "batch_source_config.0.discovery_triggerer_config",
+						`{"__CRON__":"0 * * * * *"}`),
+				),
+			},
+		},
+	})
+}
+
+func testPulsarBatchSource(url, name, cron string) string {
+	return testPulsarBatchSourceWithConfig(url, name, fmt.Sprintf(`jsonencode({ "__CRON__" = "%s" })`, cron))
+}
+
+func testPulsarBatchSourceWithConfig(url, name, triggererConfig string) string {
+	return fmt.Sprintf(`
+provider "pulsar" {
+  web_service_url = "%s"
+}
+
+resource "pulsar_source" "batch" {
+  name                   = "%s"
+  tenant                 = "public"
+  namespace              = "default"
+  archive                = "%s"
+  classname              = "org.apache.pulsar.io.datagenerator.DataGeneratorBatchSource"
+  destination_topic_name = "persistent://public/default/batch-%s"
+
+  batch_source_config {
+    discovery_triggerer_classname = "org.apache.pulsar.io.batchdiscovery.CronTriggerer"
+    discovery_triggerer_config    = %s
+  }
+}
+`, url, name, testdataBatchSourceArchive, name, triggererConfig)
+}
+
+func TestSourceUnit(t *testing.T) {
+	fake := newFakeAdminServer(t)
+	resourceName := "pulsar_source.batch"
+	sourcePath := "/admin/v3/sources/public/default/generator"
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testUnitPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: fake.checkDestroyed("pulsar_source", func(id string) string {
+			return "/admin/v3/sources/" + id
+		}),
+		Steps: []resource.TestStep{
+			{
+				Config: testPulsarBatchSource(fake.URL, "generator", "0 * * * * *"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "batch_source_config.#", "1"),
+				),
+			},
+			{
+				Config: testPulsarBatchSource(fake.URL, "generator", "*/5 * * * * *"),
+				Check: resource.ComposeTestCheckFunc(
+					func(s *terraform.State) error {
+						var sourceConfig utils.SourceConfig
+						fake.get(sourcePath, &sourceConfig)
+						if sourceConfig.BatchSourceConfig == nil ||
+							sourceConfig.BatchSourceConfig.DiscoveryTriggererConfig["__CRON__"] != "*/5 * * * * *" {
+							return fmt.Errorf("expected the cron to be updated, got %+v", sourceConfig.BatchSourceConfig)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateId:           "public/default/generator",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"wait_for_running"},
+			},
+			{
+				PreConfig: func() {
+					var sourceConfig map[string]interface{}
+					fake.get(sourcePath, &sourceConfig)
+					sourceConfig["topicName"] = "elsewhere"
+					fake.put(sourcePath, sourceConfig)
+				},
+				Config:             testPulsarBatchSource(fake.URL, "generator", "*/5 * * * * *"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestSourceBatchConfigUnit(t *testing.T) {
+	fake := newFakeAdminServer(t)
+	sourcePath := "/admin/v3/sources/public/default/generator"
+	// the keys are not sorted like the config read back from the worker
+	config := `"{\"z\":\"1\",\"__CRON__\":\"0 * * * * *\"}"`
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testUnitPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: fake.checkDestroyed("pulsar_source", func(id string) string {
+			return "/admin/v3/sources/" + id
+		}),
+		Steps: []resource.TestStep{
+			{
+				Config: testPulsarBatchSourceWithConfig(fake.URL, "generator", config),
+			},
+			{
+				Config:             testPulsarBatchSourceWithConfig(fake.URL, "generator", config),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: false,
+			},
+			{
+				// the batch source config removed out of band is planned again
+				PreConfig: func() {
+					var sourceConfig map[string]interface{}
+					fake.get(sourcePath, &sourceConfig)
+					delete(sourceConfig, "batchSourceConfig")
+					fake.put(sourcePath, sourceConfig)
+				},
+				Config:             testPulsarBatchSourceWithConfig(fake.URL, "generator", config),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestSourceValidationUnit(t *testing.T) {
+	fake := newFakeAdminServer(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testUnitPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testPulsarSourceValidation(fake.URL, "source-topic"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("ERROR_INVALID_TOPIC_NAME: destination_topic_name"),
+			},
+		},
+	})
+}
+
+func testPulsarSourceValidation(url, topic string) string {
+	return testFakeProvider(url) + fmt.Sprintf(`
+resource "pulsar_source" "test" {
+  name                   = "generator"
+  tenant                 = "public"
+  namespace              = "default"
+  archive                = "%s"
+  destination_topic_name = "%s"
+}
+`, testdataBatchSourceArchive, topic)
+}
+
+func TestSourceProducerConfig(t *testing.T) {
+	if resourcePulsarSource().Schema[producerConfigCompressionTypeKey].Computed {
+		t.Fatal("a compression type removed from a source must be planned")
+	}
+
+	d := resourcePulsarSource().TestResourceData()
+	if err := d.Set(producerConfigEncryptionKeysKey, []interface{}{"key"}); err != nil {
+		t.Fatal(err)
+	}
+	producerConfig, err := marshalProducerConfig(d.GetOk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if producerConfig.CryptoConfig == nil || len(producerConfig.CryptoConfig.EncryptionKeys) != 1 {
+		t.Fatalf("expected the crypto config to be sent without a class name, got %+v", producerConfig.CryptoConfig)
+	}
+}
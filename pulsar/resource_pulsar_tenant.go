@@ -20,10 +20,13 @@ package pulsar
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/rest"
 	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/utils"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -36,14 +39,20 @@ func resourcePulsarTenant() *schema.Resource {
 		DeleteContext: resourcePulsarTenantDelete,
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-				_ = d.Set("tenant", d.Id())
+				tenant := d.Id()
+				_ = d.Set("tenant", tenant)
+				_ = d.Set("force_destroy", false)
 				err := resourcePulsarTenantRead(ctx, d, meta)
 				if err.HasError() {
-					return nil, fmt.Errorf("import %q: %s", d.Id(), err[0].Summary)
+					return nil, fmt.Errorf("import %q: %s", tenant, err[0].Summary)
+				}
+				if d.Id() == "" {
+					return nil, fmt.Errorf("import %q: ERROR_TENANT_NOT_FOUND", tenant)
 				}
 				return []*schema.ResourceData{d}, nil
 			},
 		},
+		CustomizeDiff: customizeDiffTenantAllowedClusters,
 		Schema: map[string]*schema.Schema{
 			"tenant": {
 				Type:        schema.TypeString,
@@ -62,6 +71,12 @@ func resourcePulsarTenant() *schema.Resource {
 				Description: descriptions["admin_roles"],
 				Elem:        &schema.Schema{Type: schema.TypeString},
 			},
+			"force_destroy": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: descriptions["tenant_force_destroy"],
+			},
 		},
 	}
 }
@@ -94,7 +109,9 @@ func resourcePulsarTenantRead(ctx context.Context, d *schema.ResourceData, meta
 	td, err := client.Get(tenant)
 	if err != nil {
 		if cliErr, ok := err.(rest.Error); ok && cliErr.Code == 404 {
-			return diag.Errorf("ERROR_TENANT_NOT_FOUND")
+			// the tenant was deleted outside of terraform
+			d.SetId("")
+			return nil
 		}
 		return diag.FromErr(fmt.Errorf("ERROR_READ_TENANT: %w", err))
 	}
@@ -126,19 +143,84 @@ func resourcePulsarTenantUpdate(ctx context.Context, d *schema.ResourceData, met
 
 	d.SetId(tenant)
 
+	return resourcePulsarTenantRead(ctx, d, meta)
+}
+
+// customizeDiffTenantAllowedClusters reports at plan time the allowed clusters which do not exist, the
+// brokers reject the tenant while it lists them. The clusters which are not known yet, e.g. the id of a
+// cluster created in the same apply, are left out.
+func customizeDiffTenantAllowedClusters(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.HasChange("allowed_clusters") {
+		return nil
+	}
+
+	allowedClusters := rawConfigAllowedClusters(d.GetRawConfig())
+	if len(allowedClusters) == 0 {
+		return nil
+	}
+
+	clusters, err := getClientFromMeta(meta).Clusters().List()
+	if err != nil {
+		return fmt.Errorf("ERROR_READ_CLUSTERS: %w", err)
+	}
+
+	exists := make(map[string]bool, len(clusters))
+	for _, cluster := range clusters {
+		exists[cluster] = true
+	}
+
+	var missing []string
+	for _, cluster := range allowedClusters {
+		if !exists[cluster] {
+			missing = append(missing, cluster)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("ERROR_CLUSTER_NOT_FOUND: allowed_clusters %s do not exist, reference the id of the "+
+			"pulsar_cluster of a cluster created in the same apply", strings.Join(missing, ", "))
+	}
+
 	return nil
 }
 
+// rawConfigAllowedClusters returns the known allowed clusters of the configuration
+func rawConfigAllowedClusters(raw cty.Value) []string {
+	if raw.IsNull() || !raw.IsKnown() {
+		return nil
+	}
+
+	allowedClusters := raw.GetAttr("allowed_clusters")
+	if allowedClusters.IsNull() || !allowedClusters.IsKnown() {
+		return nil
+	}
+
+	var clusters []string
+	for it := allowedClusters.ElementIterator(); it.Next(); {
+		_, cluster := it.Element()
+		if cluster.IsKnown() && !cluster.IsNull() && cluster.AsString() != "" {
+			clusters = append(clusters, cluster.AsString())
+		}
+	}
+
+	return clusters
+}
+
 func resourcePulsarTenantDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := getClientFromMeta(meta).Tenants()
 
 	tenant := d.Get("tenant").(string)
 
-	if err := deleteExistingNamespacesForTenant(tenant, meta); err != nil {
-		return diag.FromErr(fmt.Errorf("ERROR_DELETING_EXISTING_NAMESPACES_FOR_TENANT: %w", err))
+	if d.Get("force_destroy").(bool) {
+		if err := deleteExistingNamespacesForTenant(ctx, tenant, meta); err != nil {
+			return diag.FromErr(fmt.Errorf("ERROR_DELETING_EXISTING_NAMESPACES_FOR_TENANT: %w", err))
+		}
 	}
 
 	if err := client.Delete(tenant); err != nil {
+		if cliErr, ok := err.(rest.Error); ok && cliErr.Code == 409 {
+			return diag.FromErr(fmt.Errorf("ERROR_DELETE_TENANT: %w, set force_destroy to delete its namespaces", err))
+		}
 		return diag.FromErr(fmt.Errorf("ERROR_DELETE_TENANT: %w", err))
 	}
 
@@ -147,7 +229,9 @@ func resourcePulsarTenantDelete(ctx context.Context, d *schema.ResourceData, met
 	return nil
 }
 
-func deleteExistingNamespacesForTenant(tenant string, meta interface{}) error {
+// deleteExistingNamespacesForTenant deletes every namespace of the tenant, after the functions,
+// sinks, sources and topics which would prevent it
+func deleteExistingNamespacesForTenant(ctx context.Context, tenant string, meta interface{}) error {
 	client := getClientFromMeta(meta).Namespaces()
 
 	nsList, err := client.GetNamespaces(tenant)
@@ -155,18 +239,58 @@ func deleteExistingNamespacesForTenant(tenant string, meta interface{}) error {
 		return err
 	}
 
-	if len(nsList) > 0 {
-		for _, ns := range nsList {
-			if strings.Contains(ns, tenant) {
-				if err = client.DeleteNamespace(ns); err != nil {
-					return err
-				}
-				return nil
+	for _, ns := range nsList {
+		if !strings.Contains(ns, "/") {
+			ns = fmt.Sprintf("%s/%s", tenant, ns)
+		}
+
+		if err := deleteInstancesForNamespace(ctx, ns, meta); err != nil {
+			return err
+		}
+		if err := deleteTopicsForNamespace(ctx, ns, meta); err != nil {
+			return err
+		}
+
+		tflog.Info(ctx, fmt.Sprintf("deleting namespace %s of tenant %s", ns, tenant))
+		if err := client.DeleteNamespace(ns); err != nil {
+			return fmt.Errorf("namespace %s: %w", ns, err)
+		}
+	}
+
+	return nil
+}
+
+// deleteInstancesForNamespace deletes the functions, sinks and sources running in the namespace
+func deleteInstancesForNamespace(ctx context.Context, ns string, meta interface{}) error {
+	client := getV3ClientFromMeta(meta)
+	parts := strings.SplitN(ns, "/", 2)
+	tenant, namespace := parts[0], parts[1]
+
+	kinds := []struct {
+		kind   string
+		list   func(tenant, namespace string) ([]string, error)
+		delete func(tenant, namespace, name string) error
+	}{
+		{"function", client.Functions().GetFunctions, client.Functions().DeleteFunction},
+		{"sink", client.Sinks().ListSinks, client.Sinks().DeleteSink},
+		{"source", client.Sources().ListSources, client.Sources().DeleteSource},
+	}
+
+	for _, k := range kinds {
+		names, err := k.list(tenant, namespace)
+		if err != nil {
+			// the brokers answer so when the function worker is disabled
+			if cliErr, ok := err.(rest.Error); ok && (cliErr.Code == 404 || cliErr.Code == 409) {
+				tflog.Debug(ctx, fmt.Sprintf("skipping the %ss of namespace %s: %v", k.kind, ns, err))
+				continue
 			}
+			return fmt.Errorf("list %ss of namespace %s: %w", k.kind, ns, err)
+		}
 
-			fullNamespacePath := fmt.Sprintf("%s/%s", tenant, ns)
-			if err = client.DeleteNamespace(fullNamespacePath); err != nil {
-				return err
+		for _, name := range names {
+			tflog.Info(ctx, fmt.Sprintf("deleting %s %s/%s", k.kind, ns, name))
+			if err := k.delete(tenant, namespace, name); err != nil {
+				return fmt.Errorf("%s %s/%s: %w", k.kind, ns, name, err)
 			}
 		}
 	}
@@ -174,6 +298,49 @@ func deleteExistingNamespacesForTenant(tenant string, meta interface{}) error {
 	return nil
 }
 
+// deleteTopicsForNamespace deletes the topics of the namespace, the system topics are left to the
+// deletion of the namespace
+func deleteTopicsForNamespace(ctx context.Context, ns string, meta interface{}) error {
+	client := getClientFromMeta(meta).Topics()
+
+	nsName, err := utils.GetNamespaceName(ns)
+	if err != nil {
+		return err
+	}
+
+	partitioned, nonPartitioned, err := client.List(*nsName)
+	if err != nil {
+		return fmt.Errorf("list topics of namespace %s: %w", ns, err)
+	}
+
+	isPartitioned := make(map[string]bool, len(partitioned))
+	for _, topic := range partitioned {
+		isPartitioned[topic] = true
+	}
+
+	for _, topic := range append(partitioned, nonPartitioned...) {
+		// the partitions are deleted with their partitioned topic
+		if i := strings.LastIndex(topic, utils.PARTITIONEDTOPICSUFFIX); i > 0 && isPartitioned[topic[:i]] {
+			continue
+		}
+
+		topicName, err := utils.GetTopicName(topic)
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(topicName.GetLocalName(), "__") {
+			continue
+		}
+
+		tflog.Info(ctx, fmt.Sprintf("deleting topic %s", topic))
+		if err := client.Delete(*topicName, true, !isPartitioned[topic]); err != nil {
+			return fmt.Errorf("topic %s: %w", topic, err)
+		}
+	}
+
+	return nil
+}
+
 func handleHCLArrayV2(hclArray []interface{}) []string {
 	out := make([]string, 0)
 
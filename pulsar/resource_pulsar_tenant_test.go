@@ -25,13 +25,45 @@ import (
 
 	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/admin"
 	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/utils"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 func init() {
 	initTestWebServiceURL()
+
+	resource.AddTestSweepers("pulsar_tenant", &resource.Sweeper{
+		Name:         "pulsar_tenant",
+		F:            testSweepTenants,
+		Dependencies: []string{"pulsar_namespace"},
+	})
+}
+
+func testSweepTenants(url string) error {
+	client, err := sharedClient(url)
+	if err != nil {
+		return fmt.Errorf("ERROR_GETTING_PULSAR_CLIENT: %w", err)
+	}
+
+	conn := client.(admin.Client)
+
+	tenants, err := conn.Tenants().List()
+	if err != nil {
+		return fmt.Errorf("ERROR_GETTING_TENANTS: %w", err)
+	}
+
+	var errs error
+	for _, tenant := range tenants {
+		if !isSweepable(tenant) {
+			continue
+		}
+		if err := conn.Tenants().Delete(tenant); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("ERROR_DELETING_TENANT %s: %w", tenant, err))
+		}
+	}
+
+	return errs
 }
 
 func TestTenant(t *testing.T) {
@@ -51,7 +83,7 @@ func TestTenant(t *testing.T) {
 }
 
 func TestHandleExistingTenant(t *testing.T) {
-	tName := acctest.RandString(10)
+	tName := testAccRandomName()
 
 	resource.Test(t, resource.TestCase{
 		PreCheck: func() {
@@ -71,7 +103,7 @@ func TestHandleExistingTenant(t *testing.T) {
 }
 
 func TestImportExistingTenant(t *testing.T) {
-	tName := acctest.RandString(10)
+	tName := testAccRandomName()
 
 	resource.Test(t, resource.TestCase{
 		PreCheck: func() {
@@ -92,6 +124,32 @@ func TestImportExistingTenant(t *testing.T) {
 	})
 }
 
+func TestTenantForceDestroy(t *testing.T) {
+	tName := testAccRandomName()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testPulsarTenantDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testPulsarTenantForceDestroy(testWebServiceURL, tName),
+				Check:  resource.ComposeTestCheckFunc(testPulsarTenantExists("pulsar_tenant.test")),
+			},
+			{
+				PreConfig: func() {
+					createNamespace(t, tName+"/ns-1")
+					createNamespace(t, tName+"/ns-2")
+					createTopic(t, "persistent://"+tName+"/ns-1/partitioned", 2)
+					createTopic(t, "persistent://"+tName+"/ns-2/non-partitioned", 0)
+				},
+				Config: testPulsarTenantForceDestroy(testWebServiceURL, tName),
+				Check:  resource.ComposeTestCheckFunc(testPulsarTenantExists("pulsar_tenant.test")),
+			},
+		},
+	})
+}
+
 func createTenant(t *testing.T, tname string) {
 	client, err := sharedClient(testWebServiceURL)
 	if err != nil {
@@ -113,8 +171,8 @@ func testTenantImported() resource.ImportStateCheckFunc {
 			return fmt.Errorf("expected %d states, got %d: %#v", 1, len(s), s)
 		}
 
-		if len(s[0].Attributes) != 6 {
-			return fmt.Errorf("expected %d attrs, got %d: %#v", 6, len(s[0].Attributes), s[0].Attributes)
+		if len(s[0].Attributes) != 7 {
+			return fmt.Errorf("expected %d attrs, got %d: %#v", 7, len(s[0].Attributes), s[0].Attributes)
 		}
 
 		return nil
@@ -178,6 +236,20 @@ resource "pulsar_tenant" "test" {
 }`, testWebServiceURL)
 )
 
+func testPulsarTenantForceDestroy(url, tname string) string {
+	return fmt.Sprintf(`
+provider "pulsar" {
+  web_service_url = "%s"
+}
+
+resource "pulsar_tenant" "test" {
+  tenant           = "%s"
+  allowed_clusters = ["standalone"]
+  force_destroy    = true
+}
+`, url, tname)
+}
+
 func testPulsarExistingTenantConfig(url, tname string) string {
 	return fmt.Sprintf(`
 provider "pulsar" {
@@ -197,7 +269,7 @@ const (
 )
 
 func TestTenantWithAdminRoles(t *testing.T) {
-	tName := acctest.RandString(10)
+	tName := testAccRandomName()
 	resource.Test(t, resource.TestCase{
 		PreCheck: func() {
 			testAccPreCheck(t)
@@ -220,7 +292,7 @@ func TestTenantWithAdminRoles(t *testing.T) {
 }
 
 func TestTenantUpdateAdminRoles(t *testing.T) {
-	tName := acctest.RandString(10)
+	tName := testAccRandomName()
 	resource.Test(t, resource.TestCase{
 		PreCheck:                  func() { testAccPreCheck(t) },
 		ProviderFactories:         testAccProviderFactories,
@@ -247,7 +319,7 @@ func TestTenantUpdateAdminRoles(t *testing.T) {
 }
 
 func TestTenantAdminRolesDrift(t *testing.T) {
-	tName := acctest.RandString(10)
+	tName := testAccRandomName()
 	resource.Test(t, resource.TestCase{
 		PreCheck:                  func() { testAccPreCheck(t) },
 		ProviderFactories:         testAccProviderFactories,
@@ -293,3 +365,175 @@ resource "pulsar_tenant" "test" {
   allowed_clusters = ["standalone"]
   admin_roles = ["%s", "%s"]
 }`, testWebServiceURL, testPulsarTenantWithAdminRoles2, testPulsarTenantWithAdminRoles1)
+
+func TestTenantUnit(t *testing.T) {
+	fake := newFakeAdminServer(t)
+	resourceName := "pulsar_tenant.test"
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testUnitPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: fake.checkDestroyed("pulsar_tenant", func(id string) string {
+			return "/admin/v2/tenants/" + id
+		}),
+		Steps: []resource.TestStep{
+			{
+				Config: testPulsarTenantUnit(fake.URL, `["ops", "dev"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "admin_roles.#", "2"),
+					resource.TestCheckTypeSetElemAttr(resourceName, "admin_roles.*", "ops"),
+					resource.TestCheckTypeSetElemAttr(resourceName, "admin_roles.*", "dev"),
+				),
+			},
+			{
+				Config: testPulsarTenantUnit(fake.URL, `["ops"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "admin_roles.#", "1"),
+					func(s *terraform.State) error {
+						var tenant utils.TenantData
+						fake.get("/admin/v2/tenants/thanos", &tenant)
+						if len(tenant.AdminRoles) != 1 || tenant.AdminRoles[0] != "ops" {
+							return fmt.Errorf("expected the admin roles [ops], got %v", tenant.AdminRoles)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateId:     "thanos",
+				ImportStateVerify: true,
+			},
+			{
+				PreConfig: func() {
+					fake.put("/admin/v2/tenants/thanos", utils.TenantData{
+						AdminRoles:      []string{"ops", "intruder"},
+						AllowedClusters: []string{"standalone"},
+					})
+				},
+				Config:             testPulsarTenantUnit(fake.URL, `["ops"]`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				PreConfig:          func() { fake.remove("/admin/v2/tenants/thanos") },
+				Config:             testPulsarTenantUnit(fake.URL, `["ops"]`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestTenantAllowedClustersUnit(t *testing.T) {
+	fake := newFakeAdminServer(t)
+	resourceName := "pulsar_tenant.test"
+	fake.put("/admin/v2/clusters/east", map[string]interface{}{
+		"serviceUrl": "http://east:8080",
+	})
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testUnitPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: fake.checkDestroyed("pulsar_tenant", func(id string) string {
+			return "/admin/v2/tenants/" + id
+		}),
+		Steps: []resource.TestStep{
+			{
+				// a typo is reported when the tenant is planned for creation
+				Config:      testPulsarTenantAllowedClustersUnit(fake.URL, `["standalone", "esat"]`, `["ops"]`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("ERROR_CLUSTER_NOT_FOUND: allowed_clusters esat do not exist"),
+			},
+			{
+				Config: testPulsarTenantAllowedClustersUnit(fake.URL, `["standalone", "east"]`, `["ops"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "allowed_clusters.#", "2"),
+					resource.TestCheckTypeSetElemAttr(resourceName, "allowed_clusters.*", "east"),
+				),
+			},
+			{
+				Config:             testPulsarTenantAllowedClustersUnit(fake.URL, `["east", "standalone"]`, `["ops"]`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: false,
+			},
+			{
+				// the allowed clusters are only checked when they change, not when only the admin roles do
+				PreConfig:          func() { fake.remove("/admin/v2/clusters/east") },
+				Config:             testPulsarTenantAllowedClustersUnit(fake.URL, `["standalone", "east"]`, `["ops", "dev"]`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				// a cluster added to the tenant is checked too
+				Config:      testPulsarTenantAllowedClustersUnit(fake.URL, `["standalone", "west"]`, `["ops"]`),
+				ExpectError: regexp.MustCompile("ERROR_CLUSTER_NOT_FOUND: allowed_clusters west do not exist"),
+			},
+			{
+				Config: testPulsarTenantAllowedClustersUnit(fake.URL, `["standalone"]`, `["ops", "dev"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "allowed_clusters.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "admin_roles.#", "2"),
+				),
+			},
+		},
+	})
+}
+
+func TestTenantForceDestroyUnit(t *testing.T) {
+	fake := newFakeAdminServer(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testUnitPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			for _, p := range []string{
+				"/admin/v2/tenants/thanos",
+				"/admin/v2/namespaces/thanos/ns-1",
+				"/admin/v2/persistent/thanos/ns-1/orders",
+				"/admin/v3/functions/thanos/ns-2/enrich",
+			} {
+				if fake.exists(p) {
+					return fmt.Errorf("ERROR_RESOURCE_STILL_EXISTS: %s", p)
+				}
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testPulsarTenantForceDestroy(fake.URL, "thanos"),
+			},
+			{
+				PreConfig: func() {
+					fake.put("/admin/v2/namespaces/thanos/ns-1", map[string]interface{}{})
+					fake.put("/admin/v2/namespaces/thanos/ns-2", map[string]interface{}{})
+					fake.put("/admin/v2/persistent/thanos/ns-1/orders", map[string]interface{}{"partitions": 2})
+					fake.put("/admin/v2/persistent/thanos/ns-1/audit", map[string]interface{}{})
+					fake.put("/admin/v3/functions/thanos/ns-2/enrich", map[string]interface{}{})
+				},
+				Config: testPulsarTenantForceDestroy(fake.URL, "thanos"),
+			},
+		},
+	})
+}
+
+func testPulsarTenantAllowedClustersUnit(url, allowedClusters, adminRoles string) string {
+	return testFakeProvider(url) + fmt.Sprintf(`
+resource "pulsar_tenant" "test" {
+  tenant           = "thanos"
+  allowed_clusters = %s
+  admin_roles      = %s
+}
+`, allowedClusters, adminRoles)
+}
+
+func testPulsarTenantUnit(url, adminRoles string) string {
+	return testFakeProvider(url) + fmt.Sprintf(`
+resource "pulsar_tenant" "test" {
+  tenant           = "thanos"
+  allowed_clusters = ["standalone"]
+  admin_roles      = %s
+}
+`, adminRoles)
+}
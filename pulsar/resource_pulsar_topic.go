@@ -20,12 +20,17 @@ package pulsar
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 
+	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/rest"
 	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/utils"
 	"github.com/cenkalti/backoff/v4"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/pkg/errors"
+
+	"github.com/streamnative/terraform-provider-pulsar/types"
 )
 
 func resourcePulsarTopic() *schema.Resource {
@@ -65,6 +70,7 @@ func resourcePulsarTopic() *schema.Resource {
 				Description:  descriptions["partitions"],
 				ValidateFunc: validateGtEq0,
 			},
+			"permission_grant_mode": schemaPermissionGrantMode(),
 			"permission_grant": {
 				Type:     schema.TypeSet,
 				Optional: true,
@@ -104,10 +110,37 @@ func resourcePulsarTopic() *schema.Resource {
 					},
 				},
 			},
+			"offload_policies": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: descriptions["offload_policies"],
+				MaxItems:    1,
+				Elem:        schemaOffloadPolicies(),
+			},
+			"replication_clusters": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: descriptions["topic_replication_clusters"],
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateNotBlank,
+				},
+			},
+			"replicated_subscriptions": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: descriptions["replicated_subscriptions"],
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateNotBlank,
+				},
+			},
 		},
 	}
 }
 
+// resourcePulsarTopicImport accepts the short topic names, e.g. `tenant/namespace/topic`, and the
+// partitions of a partitioned topic, which import the partitioned topic itself
 func resourcePulsarTopicImport(ctx context.Context, d *schema.ResourceData,
 	meta interface{}) ([]*schema.ResourceData, error) {
 	topic, err := utils.GetTopicName(d.Id())
@@ -115,6 +148,12 @@ func resourcePulsarTopicImport(ctx context.Context, d *schema.ResourceData,
 		return nil, fmt.Errorf("ERROR_PARSE_TOPIC_NAME: %w", err)
 	}
 
+	topic, err = getPartitionedTopicOf(meta, topic)
+	if err != nil {
+		return nil, fmt.Errorf("import %q: %w", d.Id(), err)
+	}
+
+	d.SetId(topic.String())
 	_ = d.Set("tenant", topic.GetTenant())
 	_ = d.Set("namespace", topic.GetNamespace())
 	_ = d.Set("topic_type", topic.GetDomain())
@@ -124,9 +163,81 @@ func resourcePulsarTopicImport(ctx context.Context, d *schema.ResourceData,
 	if diags.HasError() {
 		return nil, fmt.Errorf("import %q: %s", d.Id(), diags[0].Summary)
 	}
+
+	if err = importTopicPolicies(d, meta, topic); err != nil {
+		return nil, fmt.Errorf("import %q: %w", d.Id(), err)
+	}
 	return []*schema.ResourceData{d}, nil
 }
 
+// getPartitionedTopicOf returns the partitioned topic owning the partition, any other topic is
+// returned unchanged
+func getPartitionedTopicOf(meta interface{}, topicName *utils.TopicName) (*utils.TopicName, error) {
+	localName := topicName.GetLocalName()
+	i := strings.LastIndex(localName, utils.PARTITIONEDTOPICSUFFIX)
+	if i <= 0 {
+		return topicName, nil
+	}
+	if _, err := strconv.Atoi(localName[i+len(utils.PARTITIONEDTOPICSUFFIX):]); err != nil {
+		return topicName, nil
+	}
+
+	parent, err := utils.GetTopicName(fmt.Sprintf("%s://%s/%s/%s", topicName.GetDomain(), topicName.GetTenant(),
+		topicName.GetNamespace(), localName[:i]))
+	if err != nil {
+		return topicName, nil
+	}
+
+	tm, err := getClientFromMeta(meta).Topics().GetMetadata(*parent)
+	if err != nil {
+		if cliErr, ok := err.(rest.Error); ok && cliErr.Code == 404 {
+			return topicName, nil
+		}
+		return nil, fmt.Errorf("ERROR_READ_TOPIC: GetMetadata: %w", err)
+	}
+	if tm.Partitions == 0 {
+		return topicName, nil
+	}
+
+	return parent, nil
+}
+
+// importTopicPolicies reads the topic level permissions and retention policies, which are only
+// refreshed by Read once they are configured
+func importTopicPolicies(d *schema.ResourceData, meta interface{}, topicName *utils.TopicName) error {
+	grants, err := getTopicLevelPermissions(meta, topicName)
+	if err != nil {
+		return fmt.Errorf("ERROR_READ_TOPIC: %w", err)
+	}
+	if len(grants) > 0 {
+		storePermissionGrant(d, grants, true)
+	}
+
+	if !topicName.IsPersistent() {
+		return nil
+	}
+
+	// the policies inherited from the namespace are left out
+	ret, err := getClientFromMeta(meta).Topics().GetRetention(*topicName, false)
+	if err != nil {
+		// 405 is returned when the topic level policies are disabled on the broker, so no override can exist
+		if cliErr, ok := err.(rest.Error); ok && cliErr.Code == 405 {
+			return nil
+		}
+		return fmt.Errorf("ERROR_READ_TOPIC: GetRetention: %w", err)
+	}
+	if ret.RetentionTimeInMinutes != 0 || ret.RetentionSizeInMB != 0 {
+		_ = d.Set("retention_policies", []interface{}{
+			map[string]interface{}{
+				"retention_time_minutes": ret.RetentionTimeInMinutes,
+				"retention_size_mb":      int(ret.RetentionSizeInMB),
+			},
+		})
+	}
+
+	return nil
+}
+
 func resourcePulsarTopicCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := getClientFromMeta(meta).Topics()
 
@@ -154,6 +265,27 @@ func resourcePulsarTopicCreate(ctx context.Context, d *schema.ResourceData, meta
 		return diag.FromErr(fmt.Errorf("ERROR_CREATE_TOPIC_RETENTION_POLICIES: %w", err))
 	}
 
+	err = retry(func() error {
+		return updateOffloadPolicies(d, meta, topicName)
+	})
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("ERROR_CREATE_TOPIC_OFFLOAD_POLICIES: %w", err))
+	}
+
+	err = retry(func() error {
+		return updateReplicationClusters(d, meta, topicName)
+	})
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("ERROR_CREATE_TOPIC_REPLICATION_CLUSTERS: %w", err))
+	}
+
+	err = retry(func() error {
+		return updateReplicatedSubscriptions(d, meta, topicName)
+	})
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("ERROR_CREATE_TOPIC_REPLICATED_SUBSCRIPTIONS: %w", err))
+	}
+
 	return resourcePulsarTopicRead(ctx, d, meta)
 }
 
@@ -181,10 +313,11 @@ func resourcePulsarTopicRead(ctx context.Context, d *schema.ResourceData, meta i
 	_ = d.Set("topic_name", topicName.GetLocalName())
 	_ = d.Set("partitions", tm.Partitions)
 
-	if permissionGrantCfg, ok := d.GetOk("permission_grant"); ok && permissionGrantCfg.(*schema.Set).Len() > 0 {
-		grants, err := client.GetPermissions(*topicName)
+	permissionGrantCfg, ok := d.GetOk("permission_grant")
+	if isPermissionGrantAuthoritative(d) || (ok && permissionGrantCfg.(*schema.Set).Len() > 0) {
+		grants, err := getTopicLevelPermissions(meta, topicName)
 		if err != nil {
-			return diag.FromErr(fmt.Errorf("ERROR_READ_TOPIC: GetPermissions: %w", err))
+			return diag.FromErr(fmt.Errorf("ERROR_READ_TOPIC: %w", err))
 		}
 
 		setPermissionGrant(d, grants)
@@ -209,6 +342,53 @@ func resourcePulsarTopicRead(ctx context.Context, d *schema.ResourceData, meta i
 		}
 	}
 
+	// non-persistent topics have no offload policies
+	if topicName.IsPersistent() {
+		var offloadPolicies types.OffloadPolicies
+		err := getRestClientFromMeta(meta).Get(topicRestEndpoint(topicName, "offloadPolicies"), &offloadPolicies)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("ERROR_READ_TOPIC: GetOffloadPolicies: %w", err))
+		}
+		_ = d.Set("offload_policies", flattenOffloadPolicies(&offloadPolicies,
+			d.Get("offload_policies").([]interface{})))
+	}
+
+	// Topic level overrides are always read so that the ones set outside of terraform show up as drift,
+	// without override the topic follows the namespace replication clusters and none is stored
+	replClusters, err := client.GetReplicationClusters(*topicName)
+	if err != nil {
+		// 405 is returned when the topic level policies are disabled on the broker, so no override can exist
+		if cliErr, ok := err.(rest.Error); !ok || cliErr.Code != 405 {
+			return diag.FromErr(fmt.Errorf("ERROR_READ_TOPIC: GetReplicationClusters: %w", err))
+		}
+	}
+	_ = d.Set("replication_clusters", replClusters)
+
+	if replSubsCfg, ok := d.GetOk("replicated_subscriptions"); ok && replSubsCfg.(*schema.Set).Len() > 0 {
+		var subscriptions map[string]utils.SubscriptionStats
+		if tm.Partitions > 0 {
+			stats, err := client.GetPartitionedStats(*topicName, false)
+			if err != nil {
+				return diag.FromErr(fmt.Errorf("ERROR_READ_TOPIC: GetPartitionedStats: %w", err))
+			}
+			subscriptions = stats.Subscriptions
+		} else {
+			stats, err := client.GetStats(*topicName)
+			if err != nil {
+				return diag.FromErr(fmt.Errorf("ERROR_READ_TOPIC: GetStats: %w", err))
+			}
+			subscriptions = stats.Subscriptions
+		}
+
+		replicatedSubscriptions := make([]string, 0)
+		for name, sub := range subscriptions {
+			if sub.IsReplicated {
+				replicatedSubscriptions = append(replicatedSubscriptions, name)
+			}
+		}
+		_ = d.Set("replicated_subscriptions", replicatedSubscriptions)
+	}
+
 	return nil
 }
 
@@ -225,7 +405,7 @@ func resourcePulsarTopicUpdate(ctx context.Context, d *schema.ResourceData, meta
 		}
 	}
 
-	if d.HasChange("permission_grant") {
+	if d.HasChange("permission_grant") || d.HasChange("permission_grant_mode") {
 		err := updatePermissionGrant(d, meta, topicName)
 		if err != nil {
 			return diag.FromErr(err)
@@ -239,6 +419,27 @@ func resourcePulsarTopicUpdate(ctx context.Context, d *schema.ResourceData, meta
 		}
 	}
 
+	if d.HasChange("offload_policies") {
+		err := updateOffloadPolicies(d, meta, topicName)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChange("replication_clusters") {
+		err := updateReplicationClusters(d, meta, topicName)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChange("replicated_subscriptions") {
+		err := updateReplicatedSubscriptions(d, meta, topicName)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	return resourcePulsarTopicRead(ctx, d, meta)
 }
 
@@ -331,33 +532,57 @@ func updatePermissionGrant(d *schema.ResourceData, meta interface{}, topicName *
 		return fmt.Errorf("ERROR_UPDATE_TOPIC_PERMISSION_GRANT: unmarshalPermissionGrants: %w", err)
 	}
 
-	for _, grant := range permissionGrants {
-		if err = client.GrantPermission(*topicName, grant.Role, grant.Actions); err != nil {
-			return fmt.Errorf("ERROR_UPDATE_TOPIC_PERMISSION_GRANT: GrantPermission: %w", err)
-		}
+	oldPermissionGrants, _ := d.GetChange("permission_grant")
+	authoritative := isPermissionGrantAuthoritative(d)
+	if len(permissionGrants) == 0 && oldPermissionGrants.(*schema.Set).Len() == 0 && !authoritative {
+		return nil
 	}
 
-	// Revoke permissions for roles removed from the set
-	oldPermissionGrants, _ := d.GetChange("permission_grant")
-	for _, oldGrant := range oldPermissionGrants.(*schema.Set).List() {
-		oldRole := oldGrant.(map[string]interface{})["role"].(string)
-		found := false
-		for _, newGrant := range permissionGrants {
-			if newGrant.Role == oldRole {
-				found = true
-				break
-			}
+	currentGrants, err := getTopicLevelPermissions(meta, topicName)
+	if err != nil {
+		return fmt.Errorf("ERROR_UPDATE_TOPIC_PERMISSION_GRANT: %w", err)
+	}
+
+	revokes, grants := diffPermissionGrants(currentGrants, permissionGrants,
+		permissionGrantRoles(oldPermissionGrants.(*schema.Set)), authoritative)
+
+	for _, role := range revokes {
+		if err = client.RevokePermission(*topicName, role); err != nil {
+			return fmt.Errorf("ERROR_UPDATE_TOPIC_PERMISSION_GRANT: RevokePermission: %w", err)
 		}
-		if !found {
-			if err = client.RevokePermission(*topicName, oldRole); err != nil {
-				return fmt.Errorf("ERROR_UPDATE_TOPIC_PERMISSION_GRANT: RevokePermission: %w", err)
-			}
+	}
+
+	for _, grant := range grants {
+		if err = client.GrantPermission(*topicName, grant.Role, grant.Actions); err != nil {
+			return fmt.Errorf("ERROR_UPDATE_TOPIC_PERMISSION_GRANT: GrantPermission: %w", err)
 		}
 	}
 
 	return nil
 }
 
+// getTopicLevelPermissions returns the grants of a topic as stored in the policies of its namespace. The
+// permissions endpoint of a topic also returns the grants inherited from the namespace, which cannot be
+// told apart from a topic grant of the same actions.
+func getTopicLevelPermissions(meta interface{}, topicName *utils.TopicName) (map[string][]utils.AuthAction, error) {
+	ns, err := utils.GetNameSpaceName(topicName.GetTenant(), topicName.GetNamespace())
+	if err != nil {
+		return nil, fmt.Errorf("GetNameSpaceName: %w", err)
+	}
+
+	policies, err := getClientFromMeta(meta).Namespaces().GetPolicies(ns.String())
+	if err != nil {
+		return nil, fmt.Errorf("GetPolicies: %w", err)
+	}
+
+	grants := make(map[string][]utils.AuthAction)
+	for role, actions := range policies.AuthPolicies.DestinationAuth[topicName.String()] {
+		grants[role] = actions
+	}
+
+	return grants, nil
+}
+
 func updateRetentionPolicies(d *schema.ResourceData, meta interface{}, topicName *utils.TopicName) error {
 	client := getClientFromMeta(meta).Topics()
 
@@ -384,6 +609,87 @@ func updateRetentionPolicies(d *schema.ResourceData, meta interface{}, topicName
 	return nil
 }
 
+func updateOffloadPolicies(d *schema.ResourceData, meta interface{}, topicName *utils.TopicName) error {
+	client := getRestClientFromMeta(meta)
+
+	offloadPoliciesConfig := d.Get("offload_policies").([]interface{})
+	if len(offloadPoliciesConfig) == 0 {
+		if d.IsNewResource() {
+			return nil
+		}
+		if err := client.Delete(topicRestEndpoint(topicName, "offloadPolicies")); err != nil {
+			return fmt.Errorf("ERROR_UPDATE_OFFLOAD_POLICIES: RemoveOffloadPolicies: %w", err)
+		}
+		return nil
+	}
+
+	if !topicName.IsPersistent() {
+		return errors.New("ERROR_UPDATE_OFFLOAD_POLICIES: SetOffloadPolicies: " +
+			"unsupported set offload policies for non-persistent topic")
+	}
+
+	offloadPolicies := unmarshalOffloadPolicies(offloadPoliciesConfig, d.GetRawConfig())
+	if err := client.Post(topicRestEndpoint(topicName, "offloadPolicies"), offloadPolicies); err != nil {
+		return fmt.Errorf("ERROR_UPDATE_OFFLOAD_POLICIES: SetOffloadPolicies: %w", err)
+	}
+
+	return nil
+}
+
+func updateReplicationClusters(d *schema.ResourceData, meta interface{}, topicName *utils.TopicName) error {
+	client := getClientFromMeta(meta).Topics()
+
+	replClusters := handleHCLArrayV2(d.Get("replication_clusters").(*schema.Set).List())
+	if len(replClusters) == 0 {
+		if d.IsNewResource() {
+			return nil
+		}
+		// fall back to the namespace replication clusters
+		if err := getRestClientFromMeta(meta).Delete(topicRestEndpoint(topicName, "replication")); err != nil {
+			return fmt.Errorf("ERROR_UPDATE_REPLICATION_CLUSTERS: RemoveReplicationClusters: %w", err)
+		}
+		return nil
+	}
+
+	if err := client.SetReplicationClusters(*topicName, replClusters); err != nil {
+		return fmt.Errorf("ERROR_UPDATE_REPLICATION_CLUSTERS: SetReplicationClusters: %w", err)
+	}
+
+	return nil
+}
+
+// updateReplicatedSubscriptions replicates the added subscriptions and stops replicating the removed
+// ones. The subscriptions which do not exist yet, e.g. on a new topic, are skipped, they are planned
+// again until they are created.
+func updateReplicatedSubscriptions(d *schema.ResourceData, meta interface{}, topicName *utils.TopicName) error {
+	client := getRestClientFromMeta(meta)
+
+	oldSubs, newSubs := d.GetChange("replicated_subscriptions")
+
+	for _, sub := range newSubs.(*schema.Set).Difference(oldSubs.(*schema.Set)).List() {
+		endpoint := topicRestEndpoint(topicName, "subscription", sub.(string), "replicatedSubscriptionStatus")
+		if err := client.Post(endpoint, true); err != nil {
+			if cliErr, ok := err.(rest.Error); ok && cliErr.Code == 404 {
+				continue
+			}
+			return fmt.Errorf("ERROR_UPDATE_REPLICATED_SUBSCRIPTIONS: %s: %w", sub, err)
+		}
+	}
+
+	for _, sub := range oldSubs.(*schema.Set).Difference(newSubs.(*schema.Set)).List() {
+		endpoint := topicRestEndpoint(topicName, "subscription", sub.(string), "replicatedSubscriptionStatus")
+		if err := client.Post(endpoint, false); err != nil {
+			// the subscription is already gone, nothing left to disable
+			if cliErr, ok := err.(rest.Error); ok && cliErr.Code == 404 {
+				continue
+			}
+			return fmt.Errorf("ERROR_UPDATE_REPLICATED_SUBSCRIPTIONS: %s: %w", sub, err)
+		}
+	}
+
+	return nil
+}
+
 func updatePartitions(d *schema.ResourceData, meta interface{}, topicName *utils.TopicName, partitions int) error {
 	client := getClientFromMeta(meta).Topics()
 
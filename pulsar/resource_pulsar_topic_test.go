@@ -19,19 +19,80 @@ package pulsar
 
 import (
 	"fmt"
+	"net/http"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/admin"
+	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/admin/config"
 	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/utils"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	"github.com/streamnative/terraform-provider-pulsar/types"
 )
 
 func init() {
 	initTestWebServiceURL()
+
+	resource.AddTestSweepers("pulsar_topic", &resource.Sweeper{
+		Name:         "pulsar_topic",
+		F:            testSweepTopics,
+		Dependencies: []string{"pulsar_function", "pulsar_sink", "pulsar_source"},
+	})
+}
+
+func testSweepTopics(url string) error {
+	client, err := sharedClientWithVersion(url, config.V2)
+	if err != nil {
+		return fmt.Errorf("ERROR_GETTING_PULSAR_CLIENT: %w", err)
+	}
+
+	namespaces, err := sweepNamespaces(url)
+	if err != nil {
+		return err
+	}
+
+	var errs error
+	for _, ns := range namespaces {
+		nsName, err := utils.GetNamespaceName(ns)
+		if err != nil {
+			return fmt.Errorf("ERROR_PARSE_NAMESPACE_NAME: %w", err)
+		}
+
+		partitioned, nonPartitioned, err := client.Topics().List(*nsName)
+		if err != nil {
+			return fmt.Errorf("ERROR_GETTING_TOPIC_LIST: %w", err)
+		}
+
+		sweep := func(topic string, isNonPartitioned bool) {
+			topicName, err := utils.GetTopicName(topic)
+			if err != nil {
+				errs = multierror.Append(errs, fmt.Errorf("ERROR_PARSE_TOPIC_NAME %s: %w", topic, err))
+				return
+			}
+			if !isSweepable(topicName.GetTenant(), topicName.GetNamespace(), topicName.GetLocalName()) {
+				return
+			}
+			if err := client.Topics().Delete(*topicName, true, isNonPartitioned); err != nil {
+				errs = multierror.Append(errs, fmt.Errorf("ERROR_DELETING_TOPIC %s: %w", topic, err))
+			}
+		}
+
+		for _, topic := range partitioned {
+			sweep(topic, false)
+		}
+		for _, topic := range nonPartitioned {
+			// the partitions are deleted with their partitioned topic
+			if !strings.Contains(topic, "-partition-") {
+				sweep(topic, true)
+			}
+		}
+	}
+
+	return errs
 }
 
 func TestTopic(t *testing.T) {
@@ -60,7 +121,7 @@ func TestTopic(t *testing.T) {
 }
 
 func TestImportExistingTopic(t *testing.T) {
-	tname := acctest.RandString(10)
+	tname := testAccRandomName()
 	ttype := "persistent"
 	pnum := 10
 
@@ -79,7 +140,14 @@ func TestImportExistingTopic(t *testing.T) {
 				ImportState:      true,
 				Config:           testPulsarTopic(testWebServiceURL, tname, ttype, pnum, ""),
 				ImportStateId:    fullID,
-				ImportStateCheck: testTopicImported(),
+				ImportStateCheck: testTopicImported(fullID),
+			},
+			{
+				ResourceName:     "pulsar_topic.test",
+				ImportState:      true,
+				Config:           testPulsarTopic(testWebServiceURL, tname, ttype, pnum, ""),
+				ImportStateId:    fullID + "-partition-3",
+				ImportStateCheck: testTopicImported(fullID),
 			},
 		},
 	})
@@ -95,7 +163,7 @@ func TestPartionedTopicWithPermissionGrantUpdate(t *testing.T) {
 
 func testTopicWithPermissionGrantUpdate(t *testing.T, pnum int) {
 	resourceName := "pulsar_topic.test"
-	tname := acctest.RandString(10)
+	tname := testAccRandomName()
 	ttype := "persistent"
 
 	resource.Test(t, resource.TestCase{
@@ -145,6 +213,78 @@ func testTopicWithPermissionGrantUpdate(t *testing.T, pnum int) {
 	})
 }
 
+func TestTopicWithAuthoritativePermissionGrantUpdate(t *testing.T) {
+	resourceName := "pulsar_topic.test"
+	tname := testAccRandomName()
+	ttype := "persistent"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testPulsarTopicDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testPulsarTopic(testWebServiceURL, tname, ttype, 0,
+					`permission_grant_mode = "authoritative"
+					permission_grant {
+						role 		= "some-role-1"
+						actions = ["produce", "consume"]
+					}`),
+				Check: resource.ComposeTestCheckFunc(
+					testPulsarTopicExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "permission_grant.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "permission_grant.0.actions.#", "2"),
+				),
+			},
+			{
+				Config: testPulsarTopic(testWebServiceURL, tname, ttype, 0,
+					`permission_grant_mode = "authoritative"
+					permission_grant {
+						role 		= "some-role-1"
+						actions = ["consume"]
+					}`),
+				Check: resource.ComposeTestCheckFunc(
+					testPulsarTopicExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "permission_grant.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "permission_grant.0.role", "some-role-1"),
+					resource.TestCheckResourceAttr(resourceName, "permission_grant.0.actions.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "permission_grant.0.actions.0", "consume"),
+				),
+			},
+		},
+	})
+}
+
+func TestTopicWithReplicationClustersUpdate(t *testing.T) {
+	resourceName := "pulsar_topic.test"
+	tname := testAccRandomName()
+	ttype := "persistent"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testPulsarTopicDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testPulsarTopic(testWebServiceURL, tname, ttype, 0,
+					`replication_clusters = ["standalone"]`),
+				Check: resource.ComposeTestCheckFunc(
+					testPulsarTopicExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "replication_clusters.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "replication_clusters.0", "standalone"),
+				),
+			},
+			{
+				Config: testPulsarTopic(testWebServiceURL, tname, ttype, 0, ""),
+				Check: resource.ComposeTestCheckFunc(
+					testPulsarTopicExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "replication_clusters.#", "0"),
+				),
+			},
+		},
+	})
+}
+
 func testPulsarTopicDestroy(s *terraform.State) error {
 	client := getClientFromMeta(testAccProvider.Meta()).Topics()
 
@@ -230,12 +370,16 @@ func testPulsarTopicExists(topic string) resource.TestCheckFunc {
 	}
 }
 
-func testTopicImported() resource.ImportStateCheckFunc {
+func testTopicImported(id string) resource.ImportStateCheckFunc {
 	return func(s []*terraform.InstanceState) error {
 		if len(s) != 1 {
 			return fmt.Errorf("expected %d states, got %d: %#v", 1, len(s), s)
 		}
 
+		if s[0].ID != id {
+			return fmt.Errorf("expected the id %s, got %s", id, s[0].ID)
+		}
+
 		if len(s[0].Attributes) != 9 {
 			return fmt.Errorf("expected %d attrs, got %d: %#v", 9, len(s[0].Attributes), s[0].Attributes)
 		}
@@ -338,3 +482,282 @@ resource "pulsar_topic" "test" {
 }
 `, url, ttype, tname, pnum, permissionGrants)
 }
+
+func TestTopicUnit(t *testing.T) {
+	fake := newFakeAdminServer(t)
+	resourceName := "pulsar_topic.test"
+	topicPath := "/admin/v2/persistent/public/default/orders"
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testUnitPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: fake.checkDestroyed("pulsar_topic", func(id string) string {
+			return topicPath
+		}),
+		Steps: []resource.TestStep{
+			{
+				Config: testPulsarTopic(fake.URL, "orders", "persistent", 2, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "partitions", "2"),
+					testFakeTopicPartitions(fake, topicPath, 2),
+				),
+			},
+			{
+				Config: testPulsarTopic(fake.URL, "orders", "persistent", 4, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "partitions", "4"),
+					testFakeTopicPartitions(fake, topicPath, 4),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateId:     "persistent://public/default/orders",
+				ImportStateVerify: true,
+				// the mode only exists in the configuration
+				ImportStateVerifyIgnore: []string{"permission_grant_mode"},
+			},
+			{
+				PreConfig:     func() { fake.put(topicPath+"/permissions/app", []string{"consume"}) },
+				ResourceName:  resourceName,
+				ImportState:   true,
+				ImportStateId: "public/default/orders-partition-1",
+				ImportStateCheck: func(s []*terraform.InstanceState) error {
+					if len(s) != 1 {
+						return fmt.Errorf("expected 1 state, got %d", len(s))
+					}
+					expected := map[string]string{
+						"id":                   "persistent://public/default/orders",
+						"topic_name":           "orders",
+						"partitions":           "4",
+						"permission_grant.#":   "1",
+						"retention_policies.#": "1",
+					}
+					for k, v := range expected {
+						if s[0].Attributes[k] != v {
+							return fmt.Errorf("expected %s to be %q, got %q", k, v, s[0].Attributes[k])
+						}
+					}
+					return nil
+				},
+			},
+			{
+				PreConfig: func() {
+					fake.put(topicPath+"/retention", utils.RetentionPolicies{
+						RetentionTimeInMinutes: 5,
+						RetentionSizeInMB:      20000,
+					})
+				},
+				Config:             testPulsarTopic(fake.URL, "orders", "persistent", 4, ""),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestTopicReplicatedSubscriptionsUnit(t *testing.T) {
+	fake := newFakeAdminServer(t)
+	resourceName := "pulsar_topic.test"
+	subscriptionPath := "/admin/v2/persistent/public/default/orders/subscription/audit"
+	replicated := `replicated_subscriptions = ["audit"]`
+
+	testReplicated := func(expected bool) resource.TestCheckFunc {
+		return func(s *terraform.State) error {
+			var subscription utils.SubscriptionStats
+			fake.get(subscriptionPath, &subscription)
+			if subscription.IsReplicated != expected {
+				return fmt.Errorf("expected the replication of the subscription to be %t", expected)
+			}
+			return nil
+		}
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testUnitPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: fake.checkDestroyed("pulsar_topic", func(id string) string {
+			return "/admin/v2/persistent/public/default/orders"
+		}),
+		Steps: []resource.TestStep{
+			{
+				// the subscription does not exist on the new topic yet, it is planned again until it does
+				Config:             testPulsarTopic(fake.URL, "orders", "persistent", 0, replicated),
+				Check:              resource.TestCheckResourceAttr(resourceName, "replicated_subscriptions.#", "0"),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				PreConfig: func() { fake.put(subscriptionPath, utils.SubscriptionStats{}) },
+				Config:    testPulsarTopic(fake.URL, "orders", "persistent", 0, replicated),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "replicated_subscriptions.#", "1"),
+					testReplicated(true),
+				),
+			},
+			{
+				Config:   testPulsarTopic(fake.URL, "orders", "persistent", 0, replicated),
+				PlanOnly: true,
+			},
+			{
+				Config: testPulsarTopic(fake.URL, "orders", "persistent", 0, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "replicated_subscriptions.#", "0"),
+					testReplicated(false),
+				),
+			},
+		},
+	})
+}
+
+func TestTopicInheritedPermissionGrantUnit(t *testing.T) {
+	fake := newFakeAdminServer(t)
+	resourceName := "pulsar_topic.test"
+	topicPath := "/admin/v2/persistent/public/default/orders"
+	grants := `permission_grant_mode = "authoritative"
+	permission_grant {
+		role    = "app"
+		actions = ["consume"]
+	}`
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck: func() {
+			testUnitPreCheck(t)
+			fake.put("/admin/v2/namespaces/public/default/permissions/app", []string{"consume"})
+			fake.put("/admin/v2/namespaces/public/default/permissions/ops", []string{"produce"})
+			// the roles only granted on the namespace cannot be revoked on the topic
+			fake.injectError(http.MethodDelete, topicPath+"/permissions/ops", http.StatusPreconditionFailed, 0)
+		},
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// the grant equal to the namespace grant is read back from the topic policies
+				Config: testPulsarTopic(fake.URL, "orders", "persistent", 0, grants),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "permission_grant.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "permission_grant.0.role", "app"),
+				),
+			},
+			{
+				Config:   testPulsarTopic(fake.URL, "orders", "persistent", 0, grants),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestTopicOffloadPoliciesUnit(t *testing.T) {
+	fake := newFakeAdminServer(t)
+	resourceName := "pulsar_topic.test"
+	offloadPath := "/admin/v2/persistent/public/default/orders/offloadPolicies"
+	offloadPolicies := `offload_policies {
+		driver                  = "aws-s3"
+		bucket                  = "pulsar-offload"
+		region                  = "eu-west-1"
+		credential_secret       = "secret"
+		threshold_size_in_bytes = 0
+	}`
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testUnitPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testPulsarTopic(fake.URL, "orders", "persistent", 0, offloadPolicies),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "offload_policies.0.bucket", "pulsar-offload"),
+					resource.TestCheckResourceAttr(resourceName, "offload_policies.0.threshold_size_in_bytes", "0"),
+					// the brokers fill in the knobs which are not configured
+					resource.TestCheckResourceAttr(resourceName, "offload_policies.0.max_threads", "2"),
+					resource.TestCheckResourceAttr(resourceName, "offload_policies.0.read_priority",
+						"tiered-storage-first"),
+					func(*terraform.State) error {
+						var policies types.OffloadPolicies
+						fake.get(offloadPath, &policies)
+						if policies.ManagedLedgerOffloadThresholdInBytes == nil ||
+							policies.ManagedLedgerOffloadDeletionLagInMillis != nil {
+							return fmt.Errorf("expected only the configured knobs to be sent, got %+v", policies)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config:             testPulsarTopic(fake.URL, "orders", "persistent", 0, offloadPolicies),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: false,
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateId:     "persistent://public/default/orders",
+				ImportStateVerify: true,
+				// the credentials are never read back
+				ImportStateVerifyIgnore: []string{"permission_grant_mode", "offload_policies.0.credential_secret"},
+			},
+			{
+				PreConfig: func() {
+					var policies types.OffloadPolicies
+					fake.get(offloadPath, &policies)
+					policies.ManagedLedgerOffloadBucket = "other"
+					fake.put(offloadPath, policies)
+				},
+				Config:             testPulsarTopic(fake.URL, "orders", "persistent", 0, offloadPolicies),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testPulsarTopic(fake.URL, "orders", "persistent", 0, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "offload_policies.#", "0"),
+					func(*terraform.State) error {
+						if fake.exists(offloadPath) {
+							return fmt.Errorf("expected the offload policies to be removed")
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestTopicReplicationClustersUnit(t *testing.T) {
+	fake := newFakeAdminServer(t)
+	resourceName := "pulsar_topic.test"
+	topicPath := "/admin/v2/persistent/public/default/orders"
+	replicationClusters := `replication_clusters = ["test"]`
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck: func() {
+			testUnitPreCheck(t)
+			fake.put("/admin/v2/namespaces/public/default/replication", []string{"test"})
+		},
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testPulsarTopic(fake.URL, "orders", "persistent", 0, replicationClusters),
+				Check:  resource.TestCheckResourceAttr(resourceName, "replication_clusters.#", "1"),
+			},
+			{
+				// the namespace clusters are not taken for the override removed outside of terraform
+				PreConfig:          func() { fake.remove(topicPath + "/replication") },
+				Config:             testPulsarTopic(fake.URL, "orders", "persistent", 0, replicationClusters),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testFakeTopicPartitions(fake *fakeAdminServer, topicPath string, partitions int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		var metadata utils.PartitionedTopicMetadata
+		if !fake.get(topicPath, &metadata) {
+			return fmt.Errorf("ERROR_TOPIC_NOT_FOUND: %s", topicPath)
+		}
+		if metadata.Partitions != partitions {
+			return fmt.Errorf("expected %d partitions, got %d", partitions, metadata.Partitions)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,301 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsar
+
+import (
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/streamnative/terraform-provider-pulsar/types"
+)
+
+const (
+	OffloadReadPriorityBookkeeperFirst    = "bookkeeper-first"
+	OffloadReadPriorityTieredStorageFirst = "tiered-storage-first"
+)
+
+var offloadDrivers = []string{
+	"aws-s3",
+	"S3",
+	"google-cloud-storage",
+	"filesystem",
+	"azureblob",
+	"aliyun-oss",
+}
+
+func schemaOffloadPolicies() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"driver": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice(offloadDrivers, false),
+			},
+			"bucket": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"endpoint": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"credential_id": {
+				Type:      schema.TypeString,
+				Optional:  true,
+				Sensitive: true,
+			},
+			"credential_secret": {
+				Type:      schema.TypeString,
+				Optional:  true,
+				Sensitive: true,
+			},
+			"role": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"role_session_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"gcs_service_account_key_file": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"filesystem_uri": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"filesystem_profile_path": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"max_threads": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateGtEq0,
+			},
+			"prefetch_rounds": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateGtEq0,
+			},
+			"threshold_size_in_bytes": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateGtEq0,
+			},
+			"threshold_time_in_seconds": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateGtEq0,
+			},
+			"deletion_lag_ms": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateGtEq0,
+			},
+			"read_priority": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					OffloadReadPriorityBookkeeperFirst,
+					OffloadReadPriorityTieredStorageFirst,
+				}, false),
+			},
+		},
+	}
+}
+
+func isS3OffloadDriver(driver string) bool {
+	return strings.EqualFold(driver, "S3") || strings.EqualFold(driver, "aws-s3")
+}
+
+func isGCSOffloadDriver(driver string) bool {
+	return strings.EqualFold(driver, "google-cloud-storage")
+}
+
+// unmarshalOffloadPolicies only sends the tuning knobs which are configured, the others are left to the brokers
+func unmarshalOffloadPolicies(v []interface{}, rawConfig cty.Value) *types.OffloadPolicies {
+	var policies types.OffloadPolicies
+
+	configured := rawConfigOffloadPolicies(rawConfig)
+	isConfigured := func(key string) bool {
+		return !configured.IsNull() && !configured.GetAttr(key).IsNull()
+	}
+
+	for _, item := range v {
+		data, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		driver := data["driver"].(string)
+		bucket := data["bucket"].(string)
+		region := data["region"].(string)
+		endpoint := data["endpoint"].(string)
+
+		policies.ManagedLedgerOffloadDriver = driver
+		policies.ManagedLedgerOffloadBucket = bucket
+		policies.ManagedLedgerOffloadRegion = region
+		policies.ManagedLedgerOffloadServiceEndpoint = endpoint
+
+		switch {
+		case isS3OffloadDriver(driver):
+			policies.S3ManagedLedgerOffloadBucket = bucket
+			policies.S3ManagedLedgerOffloadRegion = region
+			policies.S3ManagedLedgerOffloadServiceEndpoint = endpoint
+			policies.S3ManagedLedgerOffloadCredentialID = data["credential_id"].(string)
+			policies.S3ManagedLedgerOffloadCredentialSecret = data["credential_secret"].(string)
+			policies.S3ManagedLedgerOffloadRole = data["role"].(string)
+			policies.S3ManagedLedgerOffloadRoleSessionName = data["role_session_name"].(string)
+		case isGCSOffloadDriver(driver):
+			policies.GcsManagedLedgerOffloadBucket = bucket
+			policies.GcsManagedLedgerOffloadRegion = region
+			policies.GcsManagedLedgerOffloadServiceAccountKey = data["gcs_service_account_key_file"].(string)
+		}
+
+		policies.FileSystemURI = data["filesystem_uri"].(string)
+		policies.FileSystemProfilePath = data["filesystem_profile_path"].(string)
+
+		if isConfigured("max_threads") {
+			maxThreads := data["max_threads"].(int)
+			policies.ManagedLedgerOffloadMaxThreads = &maxThreads
+		}
+		if isConfigured("prefetch_rounds") {
+			prefetchRounds := data["prefetch_rounds"].(int)
+			policies.ManagedLedgerOffloadPrefetchRounds = &prefetchRounds
+		}
+		if isConfigured("threshold_size_in_bytes") {
+			thresholdSize := int64(data["threshold_size_in_bytes"].(int))
+			policies.ManagedLedgerOffloadThresholdInBytes = &thresholdSize
+		}
+		if isConfigured("threshold_time_in_seconds") {
+			thresholdTime := int64(data["threshold_time_in_seconds"].(int))
+			policies.ManagedLedgerOffloadThresholdInSeconds = &thresholdTime
+		}
+		if isConfigured("deletion_lag_ms") {
+			deletionLag := int64(data["deletion_lag_ms"].(int))
+			policies.ManagedLedgerOffloadDeletionLagInMillis = &deletionLag
+		}
+
+		if isConfigured("read_priority") {
+			switch data["read_priority"].(string) {
+			case OffloadReadPriorityBookkeeperFirst:
+				policies.ManagedLedgerOffloadedReadPriority = "BOOKKEEPER_FIRST"
+			case OffloadReadPriorityTieredStorageFirst:
+				policies.ManagedLedgerOffloadedReadPriority = "TIERED_STORAGE_FIRST"
+			}
+		}
+	}
+
+	return &policies
+}
+
+// rawConfigOffloadPolicies returns the configured offload_policies block, null when it is not configured
+func rawConfigOffloadPolicies(raw cty.Value) cty.Value {
+	if raw.IsNull() || !raw.IsKnown() {
+		return cty.NullVal(cty.DynamicPseudoType)
+	}
+
+	offloadPolicies := raw.GetAttr("offload_policies")
+	if offloadPolicies.IsNull() || !offloadPolicies.IsKnown() || offloadPolicies.LengthInt() == 0 {
+		return cty.NullVal(cty.DynamicPseudoType)
+	}
+
+	block := offloadPolicies.Index(cty.NumberIntVal(0))
+	if !block.IsKnown() {
+		return cty.NullVal(cty.DynamicPseudoType)
+	}
+	return block
+}
+
+// flattenOffloadPolicies converts the broker policies into the schema representation. The brokers may mask the
+// credentials, so those are kept from the state.
+func flattenOffloadPolicies(policies *types.OffloadPolicies, state []interface{}) []interface{} {
+	if policies == nil || len(policies.ManagedLedgerOffloadDriver) == 0 {
+		return []interface{}{}
+	}
+
+	current := map[string]interface{}{}
+	if len(state) > 0 && state[0] != nil {
+		current = state[0].(map[string]interface{})
+	}
+	stateString := func(key string) string {
+		if v, ok := current[key].(string); ok {
+			return v
+		}
+		return ""
+	}
+	firstNotEmpty := func(values ...string) string {
+		for _, v := range values {
+			if len(v) > 0 {
+				return v
+			}
+		}
+		return ""
+	}
+
+	data := map[string]interface{}{
+		"driver": policies.ManagedLedgerOffloadDriver,
+		"bucket": firstNotEmpty(policies.ManagedLedgerOffloadBucket, policies.S3ManagedLedgerOffloadBucket,
+			policies.GcsManagedLedgerOffloadBucket),
+		"region": firstNotEmpty(policies.ManagedLedgerOffloadRegion, policies.S3ManagedLedgerOffloadRegion,
+			policies.GcsManagedLedgerOffloadRegion),
+		"endpoint": firstNotEmpty(policies.ManagedLedgerOffloadServiceEndpoint,
+			policies.S3ManagedLedgerOffloadServiceEndpoint),
+		"credential_id":                stateString("credential_id"),
+		"credential_secret":            stateString("credential_secret"),
+		"role":                         policies.S3ManagedLedgerOffloadRole,
+		"role_session_name":            policies.S3ManagedLedgerOffloadRoleSessionName,
+		"gcs_service_account_key_file": policies.GcsManagedLedgerOffloadServiceAccountKey,
+		"filesystem_uri":               policies.FileSystemURI,
+		"filesystem_profile_path":      policies.FileSystemProfilePath,
+		"read_priority": strings.ReplaceAll(strings.ToLower(policies.ManagedLedgerOffloadedReadPriority),
+			"_", "-"),
+	}
+	if policies.ManagedLedgerOffloadMaxThreads != nil {
+		data["max_threads"] = *policies.ManagedLedgerOffloadMaxThreads
+	}
+	if policies.ManagedLedgerOffloadPrefetchRounds != nil {
+		data["prefetch_rounds"] = *policies.ManagedLedgerOffloadPrefetchRounds
+	}
+	if policies.ManagedLedgerOffloadThresholdInBytes != nil {
+		data["threshold_size_in_bytes"] = int(*policies.ManagedLedgerOffloadThresholdInBytes)
+	}
+	if policies.ManagedLedgerOffloadThresholdInSeconds != nil {
+		data["threshold_time_in_seconds"] = int(*policies.ManagedLedgerOffloadThresholdInSeconds)
+	}
+	if policies.ManagedLedgerOffloadDeletionLagInMillis != nil {
+		data["deletion_lag_ms"] = int(*policies.ManagedLedgerOffloadDeletionLagInMillis)
+	}
+
+	return []interface{}{data}
+}
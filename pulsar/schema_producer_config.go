@@ -0,0 +1,254 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsar
+
+import (
+	"encoding/json"
+
+	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/utils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+)
+
+// the producer config is flattened into the source resource and nested in a producer_config block of functions
+const (
+	producerConfigMaxPendingMsgKey                = "max_pending_messages"
+	producerConfigMaxPendingMsgAcrossPartitionKey = "max_pending_messages_across_partitions"
+	producerConfigUseThreadLocalProducersKey      = "use_thread_local_producers"
+	producerConfigBatchBuilderKey                 = "batch_builder"
+	producerConfigCompressionTypeKey              = "compression_type"
+	// producer crypto config
+	producerConfigCryptoKeyReaderClassNameKey     = "crypto_key_reader_classname"
+	producerConfigCryptoKeyReaderConfigKey        = "crypto_key_reader_config"
+	producerConfigEncryptionKeysKey               = "encryption_keys"
+	producerConfigProducerCryptoFailureActionKey  = "producer_crypto_failure_action"
+	producerConfigSConsumerCryptoFailureActionKey = "consumer_crypto_failure_action"
+)
+
+var producerConfigDescriptions = make(map[string]string)
+
+func init() {
+	//nolint:lll
+	producerConfigDescriptions = map[string]string{
+		producerConfigMaxPendingMsgKey:                "The maximum size of a queue holding pending messages",
+		producerConfigMaxPendingMsgAcrossPartitionKey: "The maximum number of pending messages across partitions",
+		producerConfigUseThreadLocalProducersKey:      "Whether to use thread local producers",
+		producerConfigBatchBuilderKey:                 "BatchBuilder provides two types of batch construction methods, DEFAULT and KEY_BASED.",
+		producerConfigCompressionTypeKey:              "Set the compression type for the producer. By default, message payloads are not compressed. Supported compression types are: LZ4, ZLIB, ZSTD, SNAPPY and NONE",
+		producerConfigCryptoKeyReaderClassNameKey:     "The classname for the crypto key reader that can be used to access the keys in the keystore",
+		producerConfigCryptoKeyReaderConfigKey:        "The config for the crypto key reader that can be used to access the keys in the keystore",
+		producerConfigEncryptionKeysKey:               "One or more public keys to encrypt data key. It can be used to encrypt data key with multiple keys.",
+		producerConfigProducerCryptoFailureActionKey:  "The desired action if producer fail to encrypt data, one of FAIL, SEND",
+		producerConfigSConsumerCryptoFailureActionKey: "The desired action if consumer fail to decrypt data, one of FAIL, DISCARD, CONSUME",
+	}
+}
+
+func schemaProducerConfig() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		producerConfigMaxPendingMsgKey: {
+			Type:        schema.TypeInt,
+			Optional:    true,
+			Description: producerConfigDescriptions[producerConfigMaxPendingMsgKey],
+		},
+		producerConfigMaxPendingMsgAcrossPartitionKey: {
+			Type:        schema.TypeInt,
+			Optional:    true,
+			Description: producerConfigDescriptions[producerConfigMaxPendingMsgAcrossPartitionKey],
+		},
+		producerConfigUseThreadLocalProducersKey: {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: producerConfigDescriptions[producerConfigUseThreadLocalProducersKey],
+		},
+		producerConfigBatchBuilderKey: {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: producerConfigDescriptions[producerConfigBatchBuilderKey],
+		},
+		producerConfigCompressionTypeKey: {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: producerConfigDescriptions[producerConfigCompressionTypeKey],
+		},
+		producerConfigCryptoKeyReaderClassNameKey: {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: producerConfigDescriptions[producerConfigCryptoKeyReaderClassNameKey],
+		},
+		producerConfigCryptoKeyReaderConfigKey: {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Description:  producerConfigDescriptions[producerConfigCryptoKeyReaderConfigKey],
+			ValidateFunc: jsonValidateFunc,
+		},
+		producerConfigEncryptionKeysKey: {
+			Type:        schema.TypeSet,
+			Optional:    true,
+			Description: producerConfigDescriptions[producerConfigEncryptionKeysKey],
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		producerConfigProducerCryptoFailureActionKey: {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: producerConfigDescriptions[producerConfigProducerCryptoFailureActionKey],
+		},
+		producerConfigSConsumerCryptoFailureActionKey: {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: producerConfigDescriptions[producerConfigSConsumerCryptoFailureActionKey],
+		},
+	}
+}
+
+// marshalProducerConfig reads the producer config attributes through getOk, which is either
+// ResourceData.GetOk or a lookup in a producer_config block
+func marshalProducerConfig(getOk func(key string) (interface{}, bool)) (*utils.ProducerConfig, error) {
+	producerConfig := &utils.ProducerConfig{}
+	if inter, ok := getOk(producerConfigMaxPendingMsgKey); ok {
+		producerConfig.MaxPendingMessages = inter.(int)
+	}
+
+	if inter, ok := getOk(producerConfigMaxPendingMsgAcrossPartitionKey); ok {
+		producerConfig.MaxPendingMessagesAcrossPartitions = inter.(int)
+	}
+
+	if inter, ok := getOk(producerConfigUseThreadLocalProducersKey); ok {
+		producerConfig.UseThreadLocalProducers = inter.(bool)
+	}
+
+	if inter, ok := getOk(producerConfigBatchBuilderKey); ok {
+		producerConfig.BatchBuilder = inter.(string)
+	}
+
+	if inter, ok := getOk(producerConfigCompressionTypeKey); ok {
+		producerConfig.CompressionType = inter.(string)
+	}
+
+	cryptoConfig := &utils.CryptoConfig{}
+	if inter, ok := getOk(producerConfigCryptoKeyReaderClassNameKey); ok {
+		cryptoConfig.CryptoKeyReaderClassName = inter.(string)
+	}
+
+	if inter, ok := getOk(producerConfigCryptoKeyReaderConfigKey); ok {
+		var cryptoKeyReaderConfig map[string]interface{}
+		cryptoKeyReaderConfigJSON := inter.(string)
+
+		err := json.Unmarshal([]byte(cryptoKeyReaderConfigJSON), &cryptoKeyReaderConfig)
+		if err != nil {
+			return nil, errors.Wrapf(err,
+				"cannot unmarshal the cryptoKeyReaderConfig: %s", cryptoKeyReaderConfigJSON)
+		}
+
+		cryptoConfig.CryptoKeyReaderConfig = cryptoKeyReaderConfig
+	}
+
+	if inter, ok := getOk(producerConfigEncryptionKeysKey); ok {
+		encryptionKeysSet := inter.(*schema.Set)
+		var encryptionKeys []string
+
+		for _, item := range encryptionKeysSet.List() {
+			encryptionKeys = append(encryptionKeys, item.(string))
+		}
+
+		cryptoConfig.EncryptionKeys = encryptionKeys
+	}
+
+	if inter, ok := getOk(producerConfigProducerCryptoFailureActionKey); ok {
+		cryptoConfig.ProducerCryptoFailureAction = inter.(string)
+	}
+
+	if inter, ok := getOk(producerConfigSConsumerCryptoFailureActionKey); ok {
+		cryptoConfig.ConsumerCryptoFailureAction = inter.(string)
+	}
+
+	producerConfig.CryptoConfig = cryptoConfig
+
+	return producerConfig, nil
+}
+
+// getProducerConfigBlockValue looks up a producer config attribute in a producer_config block,
+// the zero values are reported as not set like ResourceData.GetOk does
+func getProducerConfigBlockValue(data map[string]interface{}) func(key string) (interface{}, bool) {
+	return func(key string) (interface{}, bool) {
+		switch value := data[key].(type) {
+		case int:
+			return value, value != 0
+		case bool:
+			return value, value
+		case string:
+			return value, value != ""
+		case *schema.Set:
+			return value, value.Len() > 0
+		default:
+			return nil, false
+		}
+	}
+}
+
+// flattenProducerConfig returns the producer config attributes reported by the worker
+func flattenProducerConfig(producerConfig *utils.ProducerConfig) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+
+	if producerConfig.MaxPendingMessages > 0 {
+		values[producerConfigMaxPendingMsgKey] = producerConfig.MaxPendingMessages
+	}
+
+	if producerConfig.MaxPendingMessagesAcrossPartitions > 0 {
+		values[producerConfigMaxPendingMsgAcrossPartitionKey] = producerConfig.MaxPendingMessagesAcrossPartitions
+	}
+
+	values[producerConfigUseThreadLocalProducersKey] = producerConfig.UseThreadLocalProducers
+
+	if len(producerConfig.BatchBuilder) != 0 {
+		values[producerConfigBatchBuilderKey] = producerConfig.BatchBuilder
+	}
+
+	if len(producerConfig.CompressionType) != 0 {
+		values[producerConfigCompressionTypeKey] = producerConfig.CompressionType
+	}
+
+	if producerConfig.CryptoConfig != nil {
+		cryptoConfig := producerConfig.CryptoConfig
+
+		values[producerConfigCryptoKeyReaderClassNameKey] = cryptoConfig.CryptoKeyReaderClassName
+
+		if len(cryptoConfig.CryptoKeyReaderConfig) != 0 {
+			c, err := json.Marshal(cryptoConfig.CryptoKeyReaderConfig)
+			if err != nil {
+				return nil, errors.Wrap(err, "cannot marshal config from crypto key reader")
+			}
+			values[producerConfigCryptoKeyReaderConfigKey] = string(c)
+		}
+
+		if len(cryptoConfig.EncryptionKeys) != 0 {
+			values[producerConfigEncryptionKeysKey] = stringsToInterfaces(cryptoConfig.EncryptionKeys)
+		}
+
+		if len(cryptoConfig.ProducerCryptoFailureAction) != 0 {
+			values[producerConfigProducerCryptoFailureActionKey] = cryptoConfig.ProducerCryptoFailureAction
+		}
+
+		if len(cryptoConfig.ConsumerCryptoFailureAction) != 0 {
+			values[producerConfigSConsumerCryptoFailureActionKey] = cryptoConfig.ConsumerCryptoFailureAction
+		}
+	}
+
+	return values, nil
+}
@@ -0,0 +1,139 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pulsar
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// the secret block is shared by functions, sinks and sources, each secret is passed to the
+// secrets provider of the instances as {"<name>": {"path": "<path>", "key": "<key>"}}, which is
+// the shape expected by the kubernetes secrets provider
+const (
+	secretNameKey = "name"
+	secretPathKey = "path"
+	secretKeyKey  = "key"
+)
+
+var secretDescriptions = make(map[string]string)
+
+func init() {
+	//nolint:lll
+	secretDescriptions = map[string]string{
+		secretNameKey: "The name the secret is looked up with from the instance, e.g. `context.getSecret(name)`",
+		secretPathKey: "The path of the secret in the secrets provider, e.g. the name of the kubernetes secret",
+		secretKeyKey:  "The key of the value within the secret, e.g. the key in the data of the kubernetes secret",
+	}
+}
+
+func schemaSecret(description, conflictsWith string) *schema.Schema {
+	return &schema.Schema{
+		Type:          schema.TypeSet,
+		Optional:      true,
+		Description:   description,
+		ConflictsWith: []string{conflictsWith},
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				secretNameKey: {
+					Type:         schema.TypeString,
+					Required:     true,
+					Description:  secretDescriptions[secretNameKey],
+					ValidateFunc: validateNotBlank,
+				},
+				secretPathKey: {
+					Type:         schema.TypeString,
+					Required:     true,
+					Sensitive:    true,
+					Description:  secretDescriptions[secretPathKey],
+					ValidateFunc: validateNotBlank,
+				},
+				secretKeyKey: {
+					Type:         schema.TypeString,
+					Required:     true,
+					Sensitive:    true,
+					Description:  secretDescriptions[secretKeyKey],
+					ValidateFunc: validateNotBlank,
+				},
+			},
+		},
+	}
+}
+
+// customizeDiffSecret rejects secrets sharing a name, only one of them would reach the instances
+func customizeDiffSecret(secretKey string) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		set, ok := d.Get(secretKey).(*schema.Set)
+		if !ok {
+			return nil
+		}
+
+		names := make(map[string]bool)
+		for _, item := range set.List() {
+			name := item.(map[string]interface{})[secretNameKey].(string)
+			if name == "" {
+				// unknown until apply
+				continue
+			}
+			if names[name] {
+				return fmt.Errorf("ERROR_DUPLICATE_SECRET_NAME: %s", name)
+			}
+			names[name] = true
+		}
+
+		return nil
+	}
+}
+
+func marshalSecret(set *schema.Set) map[string]interface{} {
+	secrets := make(map[string]interface{})
+	for _, item := range set.List() {
+		data := item.(map[string]interface{})
+		secrets[data[secretNameKey].(string)] = map[string]interface{}{
+			secretPathKey: data[secretPathKey].(string),
+			secretKeyKey:  data[secretKeyKey].(string),
+		}
+	}
+	return secrets
+}
+
+// flattenSecret returns false when a secret does not have the path and key shape, such secrets
+// can only be represented with the secrets JSON string
+func flattenSecret(secrets map[string]interface{}) ([]interface{}, bool) {
+	result := make([]interface{}, 0, len(secrets))
+	for name, value := range secrets {
+		data, ok := value.(map[string]interface{})
+		if !ok || len(data) != 2 {
+			return nil, false
+		}
+		path, pathOk := data[secretPathKey].(string)
+		key, keyOk := data[secretKeyKey].(string)
+		if !pathOk || !keyOk {
+			return nil, false
+		}
+
+		result = append(result, map[string]interface{}{
+			secretNameKey: name,
+			secretPathKey: path,
+			secretKeyKey:  key,
+		})
+	}
+	return result, true
+}
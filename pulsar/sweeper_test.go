@@ -18,8 +18,16 @@
 package pulsar
 
 import (
+	"fmt"
+	"strings"
+	"testing"
+
 	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/admin"
 	common "github.com/apache/pulsar-client-go/pulsaradmin/pkg/admin/config"
+	"github.com/apache/pulsar-client-go/pulsaradmin/pkg/utils"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 )
 
 func sharedClient(url string) (interface{}, error) {
@@ -39,3 +47,163 @@ func sharedClientWithVersion(url string, version common.APIVersion) (admin.Clien
 
 	return admin.New(config)
 }
+
+// testAccResourcePrefix prefixes the names of the objects created by the acceptance tests, the sweepers only
+// delete the objects carrying it
+const testAccResourcePrefix = "tf-acc-test-"
+
+// TestMain runs the sweepers instead of the tests when the -sweep flag is set, e.g.
+// go test ./pulsar -v -sweep=http://localhost:8080
+func TestMain(m *testing.M) {
+	resource.TestMain(m)
+}
+
+func testAccRandomName() string {
+	return testAccResourcePrefix + acctest.RandString(10)
+}
+
+// isSweepable tells whether an object was created by the acceptance tests, given its name and the names
+// of its parents
+func isSweepable(names ...string) bool {
+	for _, name := range names {
+		if strings.HasPrefix(name, testAccResourcePrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// sweepNamespaces lists the namespaces of all the tenants, e.g. public/default
+func sweepNamespaces(url string) ([]string, error) {
+	client, err := sharedClientWithVersion(url, common.V2)
+	if err != nil {
+		return nil, fmt.Errorf("ERROR_GETTING_PULSAR_CLIENT: %w", err)
+	}
+
+	tenants, err := client.Tenants().List()
+	if err != nil {
+		return nil, fmt.Errorf("ERROR_GETTING_TENANTS: %w", err)
+	}
+
+	var namespaces []string
+	for _, tenant := range tenants {
+		nsList, err := client.Namespaces().GetNamespaces(tenant)
+		if err != nil {
+			return nil, fmt.Errorf("ERROR_GETTING_NAMESPACE_LIST: %w", err)
+		}
+		namespaces = append(namespaces, nsList...)
+	}
+
+	return namespaces, nil
+}
+
+// sweepInstances deletes the functions, sinks or sources created by the acceptance tests in all the namespaces
+func sweepInstances(url, kind string, list func(tenant, namespace string) ([]string, error),
+	remove func(tenant, namespace, name string) error) error {
+	namespaces, err := sweepNamespaces(url)
+	if err != nil {
+		return err
+	}
+
+	var errs error
+	for _, ns := range namespaces {
+		parts := strings.Split(ns, "/")
+		names, err := list(parts[0], parts[1])
+		if err != nil {
+			return fmt.Errorf("ERROR_GETTING_%s_LIST: %w", kind, err)
+		}
+
+		for _, name := range names {
+			if !isSweepable(parts[0], parts[1], name) {
+				continue
+			}
+			if err := remove(parts[0], parts[1], name); err != nil {
+				errs = multierror.Append(errs, fmt.Errorf("ERROR_DELETING_%s %s/%s: %w", kind, ns, name, err))
+			}
+		}
+	}
+
+	return errs
+}
+
+func TestSweepersUnit(t *testing.T) {
+	fake := newFakeAdminServer(t)
+	v2, err := sharedClientWithVersion(fake.URL, common.V2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v3, err := sharedClientWithVersion(fake.URL, common.V3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tenant := testAccRandomName()
+	if err := v2.Clusters().Create(utils.ClusterData{Name: tenant, ServiceURL: "http://localhost:8080"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := v2.Tenants().Create(utils.TenantData{Name: tenant, AllowedClusters: []string{tenant}}); err != nil {
+		t.Fatal(err)
+	}
+	for _, ns := range []string{tenant + "/ns", "public/" + testAccRandomName()} {
+		if err := v2.Namespaces().CreateNamespace(ns); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i, topic := range []string{"persistent://public/default/" + testAccRandomName(),
+		"persistent://public/default/keep", "persistent://" + tenant + "/ns/orders"} {
+		topicName, _ := utils.GetTopicName(topic)
+		if err := v2.Topics().Create(*topicName, i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, function := range []utils.FunctionConfig{
+		{Tenant: tenant, Namespace: "ns", Name: "echo"},
+		{Tenant: "public", Namespace: "default", Name: testAccRandomName()},
+		{Tenant: "public", Namespace: "default", Name: "keep"},
+	} {
+		function := function
+		if err := v3.Functions().CreateFuncWithURL(&function, "function://public/default/echo@v1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// in the order of the sweepers dependencies
+	for _, sweep := range []func(string) error{
+		testSweepFunctions, testSweepSinks, testSweepSources, testSweepTopics, testSweepNS, testSweepTenants,
+		testSweepClusters,
+	} {
+		if err := sweep(fake.URL); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for p, kept := range map[string]bool{
+		"/admin/v2/clusters/standalone":              true,
+		"/admin/v2/clusters/" + tenant:               false,
+		"/admin/v2/tenants/public":                   true,
+		"/admin/v2/tenants/" + tenant:                false,
+		"/admin/v2/namespaces/public/default":        true,
+		"/admin/v2/persistent/public/default/keep":   true,
+		"/admin/v3/functions/public/default/keep":    true,
+		"/admin/v3/functions/" + tenant + "/ns/echo": false,
+	} {
+		if fake.exists(p) != kept {
+			t.Errorf("expected %s to be kept: %t", p, kept)
+		}
+	}
+
+	namespaces, err := sweepNamespaces(fake.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(namespaces) != 1 {
+		t.Errorf("expected only public/default to be left, got %v", namespaces)
+	}
+	functions, err := v3.Functions().GetFunctions("public", "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(functions) != 1 {
+		t.Errorf("expected only the keep function to be left, got %v", functions)
+	}
+}
@@ -25,7 +25,7 @@ resource "pulsar_sink" "sink-1" {
   name = "sink-1"
   tenant = "public"
   namespace = "default"
-  inputs = ["sink-1-topic"]
+  inputs = ["persistent://public/default/sink-1-topic"]
   subscription_position = "Latest"
   cleanup_subscription = false
   parallelism = 1
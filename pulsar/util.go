@@ -1,9 +1,17 @@
 package pulsar
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"reflect"
 	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const (
@@ -28,15 +36,36 @@ func isPackageURLSupported(functionPkgURL string) bool {
 
 func jsonValidateFunc(i interface{}, s string) ([]string, []error) {
 	v := i.(string)
-	_, err := json.Marshal(v)
+	if v == "" {
+		return nil, nil
+	}
+	var value interface{}
+	err := json.Unmarshal([]byte(v), &value)
 	if err != nil {
 		return nil, []error{
-			fmt.Errorf("cannot marshal %s: %s", v, err.Error()),
+			fmt.Errorf("%s is not a valid JSON string: %s", s, err.Error()),
 		}
 	}
 	return nil, nil
 }
 
+// jsonDiffSuppressFunc ignores the differences of key order, whitespaces and numeric formatting
+// between two JSON strings
+func jsonDiffSuppressFunc(k, old, new string, d *schema.ResourceData) bool {
+	if old == "" || new == "" {
+		return old == new
+	}
+
+	var oldValue, newValue interface{}
+	if err := json.Unmarshal([]byte(old), &oldValue); err != nil {
+		return false
+	}
+	if err := json.Unmarshal([]byte(new), &newValue); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(oldValue, newValue)
+}
+
 func ignoreServerSetCustomRuntimeOptions(tfGenString string, readString string) (string, error) {
 	tfGenMap := make(map[string]interface{})
 	err := json.Unmarshal([]byte(tfGenString), &tfGenMap)
@@ -60,3 +89,118 @@ func ignoreServerSetCustomRuntimeOptions(tfGenString string, readString string)
 	}
 	return string(s), nil
 }
+
+// sameStrings reports whether both slices hold the same values, regardless of the order
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	counts := make(map[string]int, len(a))
+	for _, v := range a {
+		counts[v]++
+	}
+	for _, v := range b {
+		if counts[v] == 0 {
+			return false
+		}
+		counts[v]--
+	}
+
+	return true
+}
+
+func stringsToInterfaces(values []string) []interface{} {
+	out := make([]interface{}, len(values))
+	for i, v := range values {
+		out[i] = v
+	}
+	return out
+}
+
+// toStringMap converts a TypeMap of strings
+func toStringMap(values map[string]interface{}) map[string]string {
+	if len(values) == 0 {
+		return nil
+	}
+
+	out := make(map[string]string, len(values))
+	for k, v := range values {
+		out[k] = v.(string)
+	}
+	return out
+}
+
+// flattenStringMap converts a map of strings to set a TypeMap
+func flattenStringMap(values map[string]string) map[string]interface{} {
+	out := make(map[string]interface{}, len(values))
+	for k, v := range values {
+		out[k] = v
+	}
+	return out
+}
+
+// isLocalArchive reports whether the archive is a file uploaded from the machine running terraform,
+// as opposed to a package url or a builtin connector
+func isLocalArchive(archive string) bool {
+	return archive != "" && !isPackageURLSupported(archive) && !strings.HasPrefix(archive, "builtin://")
+}
+
+// archiveSHA256 returns the hex encoded sha256 of a local archive, or an empty string for the other archives
+func archiveSHA256(archive string) (string, error) {
+	if !isLocalArchive(archive) {
+		return "", nil
+	}
+
+	f, err := os.Open(archive)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// customizeDiffArchiveSHA256 plans an update when the content of the local archive changes while its path
+// stays the same. The first non empty archive key is hashed.
+func customizeDiffArchiveSHA256(shaKey string, archiveKeys ...string) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+		var archive string
+		for _, key := range archiveKeys {
+			// the archive produced by another resource is only known during the apply
+			if !diff.NewValueKnown(key) {
+				return diff.SetNewComputed(shaKey)
+			}
+			if v, ok := diff.GetOk(key); ok {
+				archive = v.(string)
+				break
+			}
+		}
+
+		if !isLocalArchive(archive) {
+			if diff.Get(shaKey).(string) != "" {
+				return diff.SetNew(shaKey, "")
+			}
+			return nil
+		}
+
+		sum, err := archiveSHA256(archive)
+		if err != nil {
+			if os.IsNotExist(err) {
+				// the archive may be produced later in the same apply
+				return diff.SetNewComputed(shaKey)
+			}
+			return fmt.Errorf("ERROR_READ_ARCHIVE: %w", err)
+		}
+
+		if sum != diff.Get(shaKey).(string) {
+			return diff.SetNew(shaKey, sum)
+		}
+		return nil
+	}
+}
@@ -23,17 +23,18 @@ import (
 
 type (
 	// configurable features of the Pulsar Namespace Entity via Terraform
+	// the limits are nil when they are not configured, they are left to the brokers then
 	NamespaceConfig struct {
 		AntiAffinity                string
 		ReplicationClusters         []string
-		MaxConsumersPerTopic        int
-		MaxProducersPerTopic        int
-		MaxConsumersPerSubscription int
-		MessageTTLInSeconds         int
+		MaxConsumersPerTopic        *int
+		MaxProducersPerTopic        *int
+		MaxConsumersPerSubscription *int
+		MessageTTLInSeconds         *int
 		SchemaValidationEnforce     bool
 		SchemaCompatibilityStrategy string
 		IsAllowAutoUpdateSchema     bool
-		OffloadThresholdSizeInMb    int
+		OffloadThresholdSizeInMb    *int
 	}
 
 	SplitNS struct {
@@ -45,4 +46,56 @@ type (
 		Role    string
 		Actions []utils.AuthAction
 	}
+
+	// OffloadPolicies mirrors the broker's OffloadPoliciesImpl, only the fields managed via Terraform are listed
+	OffloadPolicies struct {
+		ManagedLedgerOffloadDriver               string `json:"managedLedgerOffloadDriver,omitempty"`
+		ManagedLedgerOffloadMaxThreads           *int   `json:"managedLedgerOffloadMaxThreads,omitempty"`
+		ManagedLedgerOffloadPrefetchRounds       *int   `json:"managedLedgerOffloadPrefetchRounds,omitempty"`
+		ManagedLedgerOffloadThresholdInBytes     *int64 `json:"managedLedgerOffloadThresholdInBytes,omitempty"`
+		ManagedLedgerOffloadThresholdInSeconds   *int64 `json:"managedLedgerOffloadThresholdInSeconds,omitempty"`
+		ManagedLedgerOffloadDeletionLagInMillis  *int64 `json:"managedLedgerOffloadDeletionLagInMillis,omitempty"`
+		ManagedLedgerOffloadedReadPriority       string `json:"managedLedgerOffloadedReadPriority,omitempty"`
+		ManagedLedgerOffloadBucket               string `json:"managedLedgerOffloadBucket,omitempty"`
+		ManagedLedgerOffloadRegion               string `json:"managedLedgerOffloadRegion,omitempty"`
+		ManagedLedgerOffloadServiceEndpoint      string `json:"managedLedgerOffloadServiceEndpoint,omitempty"`
+		S3ManagedLedgerOffloadBucket             string `json:"s3ManagedLedgerOffloadBucket,omitempty"`
+		S3ManagedLedgerOffloadRegion             string `json:"s3ManagedLedgerOffloadRegion,omitempty"`
+		S3ManagedLedgerOffloadServiceEndpoint    string `json:"s3ManagedLedgerOffloadServiceEndpoint,omitempty"`
+		S3ManagedLedgerOffloadCredentialID       string `json:"s3ManagedLedgerOffloadCredentialId,omitempty"`
+		S3ManagedLedgerOffloadCredentialSecret   string `json:"s3ManagedLedgerOffloadCredentialSecret,omitempty"`
+		S3ManagedLedgerOffloadRole               string `json:"s3ManagedLedgerOffloadRole,omitempty"`
+		S3ManagedLedgerOffloadRoleSessionName    string `json:"s3ManagedLedgerOffloadRoleSessionName,omitempty"`
+		GcsManagedLedgerOffloadBucket            string `json:"gcsManagedLedgerOffloadBucket,omitempty"`
+		GcsManagedLedgerOffloadRegion            string `json:"gcsManagedLedgerOffloadRegion,omitempty"`
+		GcsManagedLedgerOffloadServiceAccountKey string `json:"gcsManagedLedgerOffloadServiceAccountKeyFile,omitempty"`
+		FileSystemProfilePath                    string `json:"fileSystemProfilePath,omitempty"`
+		FileSystemURI                            string `json:"fileSystemURI,omitempty"`
+	}
+
+	// ClusterData mirrors the broker's ClusterDataImpl, the client library only knows about the service urls
+	ClusterData struct {
+		ServiceURL                         string   `json:"serviceUrl,omitempty"`
+		ServiceURLTls                      string   `json:"serviceUrlTls,omitempty"`
+		BrokerServiceURL                   string   `json:"brokerServiceUrl,omitempty"`
+		BrokerServiceURLTls                string   `json:"brokerServiceUrlTls,omitempty"`
+		ProxyServiceURL                    string   `json:"proxyServiceUrl,omitempty"`
+		ProxyProtocol                      string   `json:"proxyProtocol,omitempty"`
+		AuthenticationPlugin               string   `json:"authenticationPlugin,omitempty"`
+		AuthenticationParameters           string   `json:"authenticationParameters,omitempty"`
+		PeerClusterNames                   []string `json:"peerClusterNames,omitempty"`
+		BrokerClientTLSEnabled             bool     `json:"brokerClientTlsEnabled,omitempty"`
+		TLSAllowInsecureConnection         bool     `json:"tlsAllowInsecureConnection,omitempty"`
+		BrokerClientTLSEnabledWithKeyStore bool     `json:"brokerClientTlsEnabledWithKeyStore,omitempty"`
+		BrokerClientTLSTrustStoreType      string   `json:"brokerClientTlsTrustStoreType,omitempty"`
+		BrokerClientTLSTrustStore          string   `json:"brokerClientTlsTrustStore,omitempty"`
+		BrokerClientTLSTrustStorePassword  string   `json:"brokerClientTlsTrustStorePassword,omitempty"`
+		BrokerClientTLSKeyStoreType        string   `json:"brokerClientTlsKeyStoreType,omitempty"`
+		BrokerClientTLSKeyStore            string   `json:"brokerClientTlsKeyStore,omitempty"`
+		BrokerClientTLSKeyStorePassword    string   `json:"brokerClientTlsKeyStorePassword,omitempty"`
+		BrokerClientTrustCertsFilePath     string   `json:"brokerClientTrustCertsFilePath,omitempty"`
+		BrokerClientCertificateFilePath    string   `json:"brokerClientCertificateFilePath,omitempty"`
+		BrokerClientKeyFilePath            string   `json:"brokerClientKeyFilePath,omitempty"`
+		ListenerName                       string   `json:"listenerName,omitempty"`
+	}
 )